@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/takiz/trango/metainfo"
+	"github.com/takiz/trango/rpc"
+)
+
+const (
+	// Short, the data of a magnet torrent can be downloaded from the moment
+	// it has the metadata until it is stopped.
+	METADATA_INT     = 250 * time.Millisecond
+	DOWNLOAD_TIMEOUT = 30 * time.Second
+)
+
+// Results of adding a torrent.
+const (
+	ADD_ADDED = iota
+	ADD_DUPLICATE
+	ADD_CANCELLED
+)
+
+// Options of -add, the same for all torrents of one invocation.
+type AddOptions struct {
+	Files     string
+	Category  string
+	Dir       string
+	Start     bool
+	Dialog    bool
+	Priority  int
+	PeerLimit int
+}
+
+// Request of the running interface for the source with these options.
+func (o AddOptions) Request(source string) *AddRequest {
+	return &AddRequest{Source: source, Files: o.Files, Dir: o.Dir,
+		Category: o.Category, Start: o.Start, Priority: o.Priority,
+		PeerLimit: o.PeerLimit}
+}
+
+// Replace "-" with the sources read from the standard input, one per line,
+// and the patterns of local files with their matches.
+func ExpandSources(sources []string) ([]string, error) {
+	var res []string
+	for _, s := range sources {
+		switch {
+		case s == "-":
+			sc := bufio.NewScanner(os.Stdin)
+			for sc.Scan() {
+				if t := strings.TrimSpace(sc.Text()); t != "" {
+					res = append(res, t)
+				}
+			}
+			if err := sc.Err(); err != nil {
+				return nil, err
+			}
+		case IsLocalFile(s) && strings.ContainsAny(s, "*?["):
+			// A file name like "Movie [1080p].torrent" is not a pattern.
+			if _, err := os.Stat(s); err == nil {
+				res = append(res, s)
+				break
+			}
+			m, err := filepath.Glob(s)
+			if err != nil {
+				return nil, errors.New(s + ": " + err.Error())
+			}
+			if len(m) == 0 {
+				// Reported by AddOne.
+				m = []string{s}
+			}
+			res = append(res, m...)
+		default:
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+// Hand the sources to the running interface, which shows their add dialogs,
+// and print the sent ones. Return the others, which still need the daemon.
+func HandOff(sources []string, opts AddOptions) []string {
+	var rest []string
+	for _, s := range sources {
+		source, err := AbsSource(s)
+		if err != nil || !SendAdd(opts.Request(source)) {
+			rest = append(rest, s)
+			continue
+		}
+		fmt.Printf("%s: %s\n", P("Sent to the running trango"), s)
+	}
+	return rest
+}
+
+// Add all sources with one session and print the result of each one.
+// Return the number of failed ones.
+func AddAll(sources []string, opts AddOptions) int {
+	text := map[int]string{
+		ADD_ADDED:     P("Added"),
+		ADD_DUPLICATE: P("Duplicate"),
+		ADD_CANCELLED: P("Cancelled"),
+	}
+	failed := 0
+	for _, s := range sources {
+		res, err := AddOne(s, opts)
+		if err != nil {
+			failed++
+			fmt.Printf("%s: %s: %v\n", P("Failed"), s, err)
+		} else {
+			fmt.Printf("%s: %s\n", text[res], s)
+		}
+	}
+	return failed
+}
+
+// The absolute path of a local torrent file, the others are unchanged.
+func AbsSource(filename string) (string, error) {
+	if !IsLocalFile(filename) {
+		return filename, nil
+	}
+	if strings.ContainsAny(filename, "*?[") {
+		if _, err := os.Stat(filename); err != nil {
+			return "", errors.New(P("No matching files"))
+		}
+	}
+	if filepath.IsAbs(filename) {
+		return filename, nil
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return pwd + "/" + filename, nil
+}
+
+// Add a torrent file, URL or magnet link, showing the add dialog first
+// if opts.Dialog is set.
+func AddOne(filename string, opts AddOptions) (int, error) {
+	notUrl := IsLocalFile(filename)
+	filename, err := AbsSource(filename)
+	if err != nil {
+		return 0, err
+	}
+	files, ctg, dir, start := opts.Files, opts.Category, opts.Dir, opts.Start
+	args := &rpc.TorrentAddArgs{PeerLimit: opts.PeerLimit,
+		BandwidthPriority: opts.Priority}
+	if opts.Dialog && strings.HasPrefix(filename, "magnet") {
+		return AddMagnet(filename, &files, &ctg, &dir, &start, args)
+	}
+	count := 0 // Number of files, unknown for a URL without the dialog.
+	if opts.Dialog {
+		var info *metainfo.MetaInfo
+		var err error
+		if notUrl {
+			info, err = metainfo.ParseFile(filename)
+		} else {
+			// The daemon gets the downloaded file, not the URL again.
+			var data []byte
+			if data, err = DownloadTorrent(filename); err != nil {
+				return 0, err
+			}
+			info, err = ParseDownloaded(filename, data)
+			args.Metainfo = base64.StdEncoding.EncodeToString(data)
+		}
+		if err != nil {
+			return 0, err
+		}
+		count = info.FileCount()
+		var cancel bool
+		ShowAddDialog(info, &files, &ctg, &dir, &start, &cancel)
+		if cancel {
+			return ADD_CANCELLED, nil
+		}
+	} else if notUrl && files != "" {
+		info, err := metainfo.ParseFile(filename)
+		if err != nil {
+			return 0, err
+		}
+		count = info.FileCount()
+	}
+	args.Paused = !start
+	args.DownloadDir = dir
+	args.PriorityHigh, args.PriorityLow = PriorityFiles()
+	res, err := AddTorrent(filename, ctg, files, count, NewAddMode(filename),
+		args)
+	if err != nil {
+		return 0, err
+	}
+	if res.Duplicate != nil {
+		return ADD_DUPLICATE, nil
+	}
+	return ADD_ADDED, nil
+}
+
+// Torrent fields needed to show the add dialog for a magnet link.
+type MagnetInfo struct {
+	Name     string  `json:"name"`
+	Metadata float64 `json:"metadataPercentComplete"`
+	Size     int64   `json:"totalSize"`
+	Files    []Files `json:"files"`
+}
+
+// Download a .torrent file, a larger one than metainfo.MaxSize is cut.
+func DownloadTorrent(rawurl string) ([]byte, error) {
+	hc := &http.Client{Timeout: DOWNLOAD_TIMEOUT}
+	resp, err := hc.Get(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(rawurl + ": " + resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, metainfo.MaxSize+1))
+}
+
+// Parse a torrent file downloaded from the URL.
+func ParseDownloaded(rawurl string, data []byte) (*metainfo.MetaInfo, error) {
+	info, err := metainfo.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", rawurl, err)
+	}
+	return info, nil
+}
+
+// Add a magnet link paused, wait for its metadata and show the add dialog
+// with the files. The choices are then applied with torrent-set and the
+// torrent is removed if the dialog is cancelled. The bandwidth priority
+// and the peer limit of args are sent with torrent-add.
+func AddMagnet(magnet string, files, ctg, dir *string, start *bool, args *rpc.TorrentAddArgs) (int, error) {
+	res, err := AddPaused(magnet, args)
+	if err != nil {
+		return 0, err
+	}
+	if res.Duplicate != nil {
+		return ADD_DUPLICATE, nil
+	}
+	id := res.Torrent().Id
+	interrupt := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		close(stop)
+	}()
+	info, err := WaitMetadata(id, func(done float64) {
+		fmt.Fprintf(os.Stderr, "\r"+P("Retrieving metadata")+": %3.0f%%",
+			done*100)
+	}, stop)
+	signal.Stop(interrupt)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		Client.TorrentRemove([]int{id}, true)
+		return 0, err
+	}
+	var cancel bool
+	ShowAddDialog(DaemonFiles(info), files, ctg, dir, start, &cancel)
+	if cancel {
+		return ADD_CANCELLED, Client.TorrentRemove([]int{id}, true)
+	}
+	high, low := PriorityFiles()
+	err = SetMagnetFiles(id, len(info.Files), *files, *ctg, *dir, *start,
+		high, low)
+	return ADD_ADDED, err
+}
+
+// Add a magnet link paused with the bandwidth priority and the peer limit
+// of args.
+func AddPaused(magnet string, args *rpc.TorrentAddArgs) (*rpc.TorrentAddResult, error) {
+	return Client.TorrentAdd(&rpc.TorrentAddArgs{
+		Filename:          magnet,
+		Paused:            true,
+		BandwidthPriority: args.BandwidthPriority,
+		PeerLimit:         args.PeerLimit,
+	})
+}
+
+// Apply the choices of the add dialog to a magnet torrent of count files,
+// high and low are the files of the high and the low priority.
+func SetMagnetFiles(id, count int, files, ctg, dir string, start bool, high, low []int) error {
+	set := map[string]interface{}{"ids": []int{id}}
+	if dir != "" {
+		set["location"] = dir
+	}
+	if ctg != "" {
+		set["labels"] = strings.Split(ctg, ",")
+	}
+	if files != "" {
+		wanted, err := ParseFileIds(files)
+		if err != nil {
+			return err
+		}
+		set["files-wanted"] = wanted
+		set["files-unwanted"] = UnwantedFiles(wanted, count)
+	}
+	if len(high) > 0 {
+		set["priority-high"] = high
+	}
+	if len(low) > 0 {
+		set["priority-low"] = low
+	}
+	if err := Client.TorrentSet(set); err != nil {
+		return err
+	}
+	if start {
+		return Client.TorrentAction("torrent-start", []int{id})
+	}
+	return nil
+}
+
+// Wait until the daemon has the metadata of the torrent, calling progress
+// with the done fraction, or until stop is closed. A stopped torrent does
+// not connect to peers, so it is started for the metadata and stopped
+// again before the files are chosen.
+func WaitMetadata(id int, progress func(done float64), stop <-chan struct{}) (*MagnetInfo, error) {
+	fields := []string{"name", "metadataPercentComplete", "totalSize",
+		"files"}
+	err := Client.TorrentAction("torrent-start", []int{id})
+	if err != nil {
+		return nil, err
+	}
+	tick := time.NewTicker(METADATA_INT)
+	defer tick.Stop()
+	for {
+		var out struct {
+			Torrents []*MagnetInfo `json:"torrents"`
+		}
+		if err := Client.TorrentGet([]int{id}, fields, &out); err != nil {
+			return nil, err
+		}
+		if len(out.Torrents) == 0 {
+			return nil, errors.New(P("Torrent was removed"))
+		}
+		info := out.Torrents[0]
+		if info.Metadata >= 1 {
+			err := Client.TorrentAction("torrent-stop", []int{id})
+			progress(info.Metadata)
+			return info, err
+		}
+		progress(info.Metadata)
+		select {
+		case <-tick.C:
+		case <-stop:
+			return nil, errors.New(P("Cancelled"))
+		}
+	}
+}
+
+// Convert the daemon file list to metainfo. The file names start with the
+// torrent name, which is the root of the tree.
+func DaemonFiles(info *MagnetInfo) *metainfo.MetaInfo {
+	m := &metainfo.MetaInfo{Name: info.Name, Length: info.Size}
+	if len(info.Files) == 1 && info.Files[0].Name == info.Name {
+		return m
+	}
+	m.Files = make([]metainfo.File, len(info.Files))
+	for i, f := range info.Files {
+		m.Files[i] = metainfo.File{
+			Path:   strings.Split(f.Name, "/")[1:],
+			Length: f.Size,
+		}
+	}
+	return m
+}
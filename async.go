@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const SPINNER_INT = 100 * time.Millisecond
+
+var (
+	Pending       int32 // Requests started by Async() and not applied yet.
+	SpinnerFrames = []string{"|", "/", "-", "\\"}
+)
+
+// Run the RPC work in a background goroutine, then call apply in the UI
+// goroutine. If the work fails, the error is shown in the status bar
+// instead.
+func Async(work func() error, apply func()) {
+	atomic.AddInt32(&Pending, 1)
+	go func() {
+		err := work()
+		App.QueueUpdateDraw(func() {
+			atomic.AddInt32(&Pending, -1)
+			if err != nil {
+				ReportError(err)
+			} else if apply != nil {
+				apply()
+			}
+		})
+	}()
+}
+
+// Show a spinner in the status bar title while requests are pending.
+func Spinner() {
+	shown := false
+	for i := 0; ; i++ {
+		time.Sleep(SPINNER_INT)
+		if atomic.LoadInt32(&Pending) == 0 {
+			if shown {
+				shown = false
+				App.QueueUpdateDraw(func() {
+					Statusbar.SetTitle("")
+				})
+			}
+			continue
+		}
+		shown = true
+		frame := " " + SpinnerFrames[i%len(SpinnerFrames)] + " "
+		App.QueueUpdateDraw(func() {
+			Statusbar.SetTitle(frame)
+		})
+	}
+}
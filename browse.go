@@ -0,0 +1,351 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/takiz/trango/metainfo"
+	"github.com/takiz/trango/rpc"
+)
+
+const TORRENT_EXT = ".torrent"
+
+// A torrent to add from the running interface with the defaults of the
+// add dialog. Other trango processes send it with -add.
+type AddRequest struct {
+	Source    string `json:"source"` // Path, URL or magnet link.
+	Files     string `json:"files,omitempty"`
+	Dir       string `json:"dir,omitempty"`
+	Category  string `json:"category,omitempty"`
+	Start     bool   `json:"start,omitempty"`
+	Priority  int    `json:"priority,omitempty"`
+	PeerLimit int    `json:"peer_limit,omitempty"`
+}
+
+// A torrent loaded for the add dialog.
+type AddSource struct {
+	*AddRequest
+	Id   int // Magnet torrent added paused, 0 for the others.
+	Info *metainfo.MetaInfo
+	Data []byte // Torrent file downloaded from the URL.
+	Dirs []string
+}
+
+// Request with the defaults of the config file.
+func NewAddRequest(source string) *AddRequest {
+	return &AddRequest{Source: source, Dir: Cfg.Dir, Category: Cfg.Category,
+		Start: Cfg.Start}
+}
+
+// Replace a leading ~ with the home dir.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + path[1:]
+}
+
+// The start dir of the file browser, ~/Downloads if it exists.
+func DefaultBrowseDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/"
+	}
+	d := filepath.Join(home, "Downloads")
+	if fi, err := os.Stat(d); err == nil && fi.IsDir() {
+		return d
+	}
+	return home
+}
+
+// Fill the list with the subdirs and the torrent files of dir, the
+// secondary text is the path.
+func FillBrowser(list *tview.List, dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	list.Clear()
+	if dir != "/" {
+		list.AddItem("  ../", filepath.Dir(dir), 0, nil)
+	}
+	var files []os.FileInfo
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if e.Mode()&os.ModeSymlink != 0 {
+			if fi, err := os.Stat(path); err == nil {
+				e = fi
+			}
+		}
+		if e.IsDir() {
+			list.AddItem("  [red:]"+tview.Escape(e.Name())+"/[-:]",
+				path, 0, nil)
+		} else if strings.HasSuffix(strings.ToLower(e.Name()), TORRENT_EXT) {
+			files = append(files, e)
+		}
+	}
+	for _, f := range files {
+		list.AddItem(fmt.Sprintf("  %s (%s)", tview.Escape(f.Name()),
+			FormatSize(f.Size())), filepath.Join(dir, f.Name()), 0, nil)
+	}
+	return nil
+}
+
+// Browse the torrent files from BrowseDir to add one of them. A magnet
+// link, a URL or a path can be typed or pasted in the input field instead.
+// The torrent of req is loaded at once if it is not nil.
+func ShowAddBrowser(req *AddRequest) {
+	ViewOpen = true
+	MainGrid.RemoveItem(MainList)
+	list := NewListPrim()
+	input := NewInputFieldPrim(P("Magnet link, URL or path") + ": ").
+		SetFieldWidth(0)
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	keys := []Key{{"Esc", P("Close")}, {"Enter", P("Open")},
+		{"Backspace", P("Parent dir")}}
+	title := func() {
+		SetKeysHeaderText(P("Add torrent")+": "+tview.Escape(BrowseDir),
+			FormatKeys(keys), tview.AlignCenter)
+	}
+	browse := func(dir string) {
+		if err := FillBrowser(list, dir); err != nil {
+			ReportError(err)
+			return
+		}
+		BrowseDir = dir
+		title()
+	}
+	title()
+	browse(BrowseDir)
+
+	// stop is closed to cancel the torrent being loaded, nil otherwise.
+	var stop chan struct{}
+	load := func(req *AddRequest) {
+		s := make(chan struct{})
+		stop = s
+		SetKeysHeaderText(P("Loading")+": "+tview.Escape(req.Source),
+			FormatKeys([]Key{{"Esc", P("Cancel")}}), tview.AlignCenter)
+		var src *AddSource
+		var err error
+		progress := func(done float64) {
+			text := fmt.Sprintf(P("Retrieving metadata")+": %3.0f%%",
+				done*100)
+			App.QueueUpdateDraw(func() {
+				if stop == s {
+					Header.SetText(text)
+				}
+			})
+		}
+		// The errors are handled here to leave the loading state.
+		Async(func() error {
+			src, err = LoadAddSource(req, progress, s)
+			return nil
+		}, func() {
+			if stop == s {
+				stop = nil
+				title()
+			}
+			if err != nil {
+				ReportError(err)
+				return
+			}
+			select {
+			case <-s:
+				if src.Id != 0 {
+					Async(func() error {
+						return Client.TorrentRemove([]int{src.Id}, true)
+					}, RequestResync)
+				}
+				return
+			default:
+			}
+			MainGrid.RemoveItem(flex)
+			ShowAddDialogView(src)
+		})
+	}
+	open := func(path string) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			ReportError(err)
+		} else if fi.IsDir() {
+			browse(path)
+		} else {
+			load(NewAddRequest(path))
+		}
+	}
+
+	MainGrid.AddItem(flex, 2, 0, 1, 3, 0, 0, true)
+	App.SetFocus(input).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if stop != nil {
+				if event.Key() == tcell.KeyEsc {
+					close(stop)
+					stop = nil
+					title()
+				}
+				return nil
+			}
+			text := strings.TrimSpace(input.GetText())
+			switch event.Key() {
+			case tcell.KeyEsc:
+				list.Clear()
+				SwitchToMain(flex, LIST)
+				ViewOpen = false
+				return nil
+			case tcell.KeyEnter:
+				if text == "" {
+					_, path := list.GetItemText(list.GetCurrentItem())
+					open(path)
+				} else if !IsLocalFile(text) {
+					load(NewAddRequest(text))
+				} else {
+					path := ExpandHome(text)
+					if !filepath.IsAbs(path) {
+						path = filepath.Join(BrowseDir, path)
+					}
+					input.SetText("")
+					open(filepath.Clean(path))
+				}
+				return nil
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if text == "" && BrowseDir != "/" {
+					browse(filepath.Dir(BrowseDir))
+					return nil
+				}
+			case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+				list.InputHandler()(event, nil)
+				return nil
+			case tcell.KeyHome, tcell.KeyEnd:
+				if text == "" {
+					list.InputHandler()(event, nil)
+					return nil
+				}
+			}
+			return event
+		})
+	if req != nil {
+		input.SetText(req.Source)
+		load(req)
+	}
+}
+
+// Read the torrent file, download it from the URL or add the magnet link
+// paused and wait for its metadata. stop cancels the waiting.
+func LoadAddSource(req *AddRequest, progress func(done float64), stop <-chan struct{}) (*AddSource, error) {
+	r := *req
+	src := &AddSource{AddRequest: &r}
+	source := r.Source
+	var err error
+	if src.Dirs, err = GetDownloadDirs(); err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.HasPrefix(source, "magnet"):
+		res, err := AddPaused(source, &rpc.TorrentAddArgs{
+			BandwidthPriority: r.Priority, PeerLimit: r.PeerLimit})
+		if err != nil {
+			return nil, err
+		}
+		if res.Duplicate != nil {
+			return nil, errors.New(P("Torrent already added"))
+		}
+		src.Id = res.Torrent().Id
+		info, err := WaitMetadata(src.Id, progress, stop)
+		if err != nil {
+			Client.TorrentRemove([]int{src.Id}, true)
+			return nil, err
+		}
+		src.Info = DaemonFiles(info)
+	case IsLocalFile(source):
+		src.Info, err = metainfo.ParseFile(source)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		if src.Data, err = DownloadTorrent(source); err != nil {
+			return nil, err
+		}
+		if src.Info, err = ParseDownloaded(source, src.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := LastDirCtg(&src.Dir, &src.Category); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// Show the add dialog of src in place of the torrent list. The dialog
+// grid is MainGrid until it is closed, then the torrent is added or the
+// paused magnet torrent is removed.
+func ShowAddDialogView(src *AddSource) {
+	mainGrid, mainHeader, mainKeys := MainGrid, Header, Hotkeys
+	files, ctg, dir, start := src.Files, src.Category, src.Dir, src.Start
+	Dirs = src.Dirs
+	var view *tview.Flex
+	dialog := NewAddDialog(src.Info, &files, &ctg, &dir, &start,
+		func(cancel bool) {
+			MainGrid, Header, Hotkeys = mainGrid, mainHeader, mainKeys
+			view.RemoveItem(Statusbar)
+			MainGrid.AddItem(Header, 1, 0, 1, 3, 0, 0, false).
+				AddItem(Statusbar, 3, 0, 1, 3, 0, 0, false)
+			SwitchToMain(view, ALL_T)
+			ViewOpen = false
+			if cancel {
+				if src.Id != 0 {
+					Async(func() error {
+						return Client.TorrentRemove([]int{src.Id}, true)
+					}, RequestResync)
+				}
+				return
+			}
+			if err := SetLast(SAVE, &dir, &ctg); err != nil {
+				ReportError(err)
+			}
+			count := src.Info.FileCount()
+			// The next add dialog replaces the globals.
+			high, low := PriorityFiles()
+			mode := NewAddMode(src.Source)
+			Async(func() error {
+				if src.Id != 0 {
+					return SetMagnetFiles(src.Id, count, files, ctg, dir,
+						start, high, low)
+				}
+				args := &rpc.TorrentAddArgs{Paused: !start, DownloadDir: dir,
+					BandwidthPriority: src.Priority, PeerLimit: src.PeerLimit,
+					PriorityHigh: high, PriorityLow: low}
+				if src.Data != nil {
+					args.Metainfo = base64.StdEncoding.EncodeToString(src.Data)
+				}
+				res, err := AddTorrent(src.Source, ctg, files, count, mode,
+					args)
+				if err == nil && res.Duplicate != nil {
+					err = errors.New(P("Torrent already added"))
+				}
+				return err
+			}, RequestResync)
+		})
+	// The status bar stays below the dialog for the errors.
+	view = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(dialog, 0, 1, true).
+		AddItem(Statusbar, 3, 0, false)
+	mainGrid.RemoveItem(mainHeader).RemoveItem(Statusbar).
+		RemoveItem(mainKeys).
+		AddItem(view, 1, 0, 4, 3, 0, 0, true)
+}
@@ -14,7 +14,10 @@ type dictionary struct {
 }
 
 func (d *dictionary) Lookup(key string) (data string, ok bool) {
-	p := messageKeyToIndex[key]
+	p, ok := messageKeyToIndex[key]
+	if !ok {
+		return "", false
+	}
 	start, end := d.index[p], d.index[p+1]
 	if start == end {
 		return "", false
@@ -36,307 +39,608 @@ func init() {
 }
 
 var messageKeyToIndex = map[string]int{
-	"   Added Date":               42,
-	"   Name":                     43,
-	"   Name ":                    27,
-	"   Progress":                 44,
-	"   Size":                     45,
-	"  Done  |  Size   |  Name ":  79,
-	"  | Peers | Seeds | Status ": 93,
-	" Category: ":                 51,
-	" Path":                       50,
-	" Size":                       56,
-	" Start torrent:":             53,
-	" |  Done  | Downloading | Uploading |   Flags   | Client": 106,
-	"(Un)expand dir":    59,
-	"(Un)pause updates": 107,
-	"<0,1,2,3,...> Mark files for download by index numbers":      5,
-	"<filename-or-URL>  Add torrent":                              4,
-	"<name1,name2,...>  Set categories when adding a new torrent": 3,
-	"<path>  Set download dir when adding a new torrent":          2,
-	"Active":                        91,
-	"Add a new tracker":             104,
-	"Add torrent":                   52,
-	"Added":                         119,
-	"All":                           14,
-	"B":                             128,
-	"Cancel":                        49,
-	"Categories":                    90,
-	"Category":                      29,
-	"Check wait":                    17,
-	"Checking":                      18,
-	"Close":                         39,
-	"Comment":                       114,
-	"Content":                       34,
-	"Created":                       117,
-	"Creator":                       118,
-	"Default":                       15,
-	"Directories":                   100,
-	"Do you really want to delete":  83,
-	"Done":                          24,
-	"Downloading":                   20,
-	"ETA":                           25,
-	"Edit URL":                      103,
-	"Enter a new category name(s):": 47,
-	"Enter a new path:":             48,
-	"Enter announce URL:":           86,
-	"Errored":                       22,
-	"Errors":                        121,
-	"Filter by category":            89,
-	"Free":                          46,
-	"General":                       30,
-	"General Info":                  110,
-	"Get":                           58,
-	"GiB":                           125,
-	"Hash":                          112,
-	"Help":                          36,
-	"Hotkeys":                       63,
-	"KiB":                           127,
-	"Location":                      113,
-	"MB/s":                          129,
-	"MiB":                           126,
-	"Move":                          35,
-	"Move to:":                      84,
-	"Name":                          111,
-	"New category":                  102,
-	"New path":                      99,
-	"Next":                          108,
-	"Next dir":                      95,
-	"Next root dir":                 96,
-	"No":                            81,
-	"Open":                          80,
-	"Path":                          61,
-	"Paused":                        123,
-	"Peers":                         32,
-	"Print current version":         13,
-	"Print tracker URLs of a torrent file to standard output": 11,
-	"Priority":                           94,
-	"Queued":                             19,
-	"Quit":                               28,
-	"Ratio":                              116,
-	"Remove tracker":                     105,
-	"Rename to:":                         85,
-	"Resumed":                            122,
-	"Search":                             33,
-	"Search:":                            109,
-	"Seeding":                            21,
-	"Select category":                    101,
-	"Select dir":                         98,
-	"Set category for selected torrents": 88,
-	"Set host":                           0,
-	"Set password":                       7,
-	"Set port":                           1,
-	"Set the interval for updating torrents information in seconds": 12,
-	"Set username": 6,
-	"Show dialog when adding a new torrent file (not url/magnet)": 10,
-	"Show full status names": 8,
-	"Sort":                   40,
-	"Sort by":                41,
-	"SortBy":                 37,
-	"Space":                  57,
-	"Start added torrent":    9,
-	"Start yes/no":           60,
-	"Status":                 23,
-	"Stopped":                16,
-	"Torrent already added":  62,
-	"Total Size":             120,
-	"Tracker URL:":           87,
-	"Trackers":               31,
-	"URL":                    92,
-	"Uploaded":               115,
-	"Uploading":              124,
-	"Yes":                    82,
-	"You need transmission-daemon version 3.00 or later for the categories support.": 38,
-	"cancel selection": 74,
-	"create a new category for selected torrent(s)": 75,
-	"d":                                 131,
-	"h":                                 132,
-	"kB/s":                              130,
-	"m":                                 133,
-	"no":                                54,
-	"open comment url":                  76,
-	"open download dir":                 77,
-	"or":                                69,
-	"preview/open file(s)":              71,
-	"reannounce":                        67,
-	"remove torrent(s)":                 68,
-	"remove torrent(s) with data":       70,
-	"rename torrent":                    78,
-	"s":                                 134,
-	"select all":                        73,
-	"select/unselect":                   72,
-	"start":                             64,
-	"stop":                              65,
-	"verify":                            66,
-	"yes":                               55,
-	"|   Size    |  Priority  |  Name ": 97,
-	"|  Uploading  | Downloading | Peers |  Done  |   Size    |": 26,
+	"   Added Date":               173,
+	"   Name":                     174,
+	"   Name ":                    159,
+	"   Progress":                 175,
+	"   Queue":                    177,
+	"   Size":                     176,
+	"  Done  |  Size   |  Name ":  224,
+	"  | Peers | Seeds | Status ": 237,
+	" Category: ":                 188,
+	" Path":                       185,
+	" Size":                       190,
+	" Start torrent:":             189,
+	" |  Done  | Downloading | Uploading |   Flags   | Client": 249,
+	"(Un)expand dir":    193,
+	"(Un)pause updates": 250,
+	"<0,1,2,3,...> Mark files for download by index numbers":                                                         129,
+	"<URL>  Set full RPC URL, e.g. https://host/transmission/rpc (overrides -host and -port)":                        121,
+	"<auto|always|never>  Send the contents of a torrent file instead of its path, auto does it for a remote daemon": 137,
+	"<dir>  Start dir of the file browser for adding torrents, ~/Downloads by default":                               138,
+	"<file>  Client certificate for TLS authentication":                                                              123,
+	"<file>  Print the contents of a torrent file, the daemon is not needed":                                         139,
+	"<file>  Private key of the client certificate":                                                                  124,
+	"<file>  Verify the daemon with CA certificates from the PEM file":                                               122,
+	"<filename-or-URL>  Add torrent, more can follow as arguments, - reads them from standard input":                 128,
+	"<filename>  Use an alternate config file":                                                                       145,
+	"<low|normal|high>  Set bandwidth priority when adding a new torrent":                                            135,
+	"<n>  Reload all torrents every n updates, only changed ones are fetched otherwise":                              143,
+	"<n>  Set peer limit when adding a new torrent":                                                                  136,
+	"<name1,name2,...>  Set categories when adding a new torrent":                                                    127,
+	"<name>  Connect with a profile from the config file":                                                            146,
+	"<path>  Set download dir when adding a new torrent":                                                             126,
+	"Active":                           235,
+	"Active downloads":                 53,
+	"Active seeds":                     55,
+	"Add a new tracker":                247,
+	"Add torrent":                      12,
+	"Added":                            1,
+	"All":                              147,
+	"Alt speed":                        92,
+	"Alternative speed":                58,
+	"Append .part to incomplete files": 72,
+	"B":                                267,
+	"Bandwidth priority":               76,
+	"Blocklist":                        49,
+	"Blocklist URL":                    50,
+	"Cancel":                           14,
+	"Cancelled":                        3,
+	"Categories":                       234,
+	"Category":                         161,
+	"Check wait":                       150,
+	"Checking":                         151,
+	"Close":                            9,
+	"Comment":                          26,
+	"Connect":                          179,
+	"Content":                          164,
+	"Created":                          256,
+	"Created by":                       27,
+	"Created on":                       28,
+	"Creator":                          257,
+	"Current session":                  112,
+	"DHT":                              41,
+	"Default":                          148,
+	"Delete added .torrent files":      74,
+	"Directories":                      243,
+	"Disconnected, retrying in %ds":    260,
+	"Do not verify the daemon TLS certificate": 125,
+	"Do you really want to delete":             227,
+	"Done":                                     156,
+	"Download dir":                             69,
+	"Download dirs":                            115,
+	"Download limit (kB/s)":                    34,
+	"Download queue":                           52,
+	"Downloaded":                               107,
+	"Downloading":                              153,
+	"Duplicate":                                2,
+	"ETA":                                      157,
+	"Edit":                                     84,
+	"Edit URL":                                 246,
+	"Encryption":                               40,
+	"Enter a new category name(s):":            186,
+	"Enter a new path:":                        187,
+	"Enter announce URL:":                      230,
+	"Errored":                                  154,
+	"Errors":                                   259,
+	"Every day":                                102,
+	"Failed":                                   4,
+	"Files":                                    31,
+	"Files added":                              109,
+	"Filter by category":                       233,
+	"Free":                                     184,
+	"Fri":                                      100,
+	"From":                                     61,
+	"General":                                  162,
+	"General Info":                             253,
+	"Get":                                      192,
+	"GiB":                                      264,
+	"Global peer limit":                        38,
+	"Hash":                                     254,
+	"Help":                                     160,
+	"High":                                     79,
+	"Honor session limits":                     75,
+	"Hotkeys":                                  198,
+	"Idle limit":                               83,
+	"Idle limit (minutes)":                     68,
+	"Incomplete dir":                           71,
+	"Info hash":                                20,
+	"Invalid URL":                              169,
+	"Invalid value":                            91,
+	"KiB":                                      266,
+	"Limit download speed":                     33,
+	"Limit upload speed":                       35,
+	"Loading":                                  13,
+	"Local peer discovery":                     43,
+	"Location":                                 255,
+	"Low":                                      77,
+	"MB/s":                                     268,
+	"Magnet link, URL or path":                 8,
+	"MiB":                                      265,
+	"Mon":                                      96,
+	"Move":                                     165,
+	"Move to:":                                 228,
+	"Name":                                     19,
+	"Network":                                  45,
+	"New category":                             245,
+	"New path":                                 242,
+	"Next":                                     251,
+	"Next dir":                                 238,
+	"Next field":                               85,
+	"Next root dir":                            239,
+	"No":                                       225,
+	"No matching files":                        5,
+	"No profiles in the config file":           178,
+	"Normal":                                   78,
+	"On days":                                  63,
+	"Open":                                     10,
+	"PEX":                                      42,
+	"Parent dir":                               11,
+	"Path":                                     195,
+	"Paused":                                   262,
+	"Peer limit":                               80,
+	"Peer limit per torrent":                   39,
+	"Peer port":                                46,
+	"Peers":                                    37,
+	"Piece size":                               22,
+	"Pieces":                                   23,
+	"Port forwarding":                          48,
+	"Print -info as JSON":                      140,
+	"Print current version":                    144,
+	"Print tracker URLs of a torrent file to standard output": 141,
+	"Priority":                           196,
+	"Private":                            24,
+	"Profiles":                           180,
+	"Queue":                              51,
+	"Queued":                             152,
+	"Quit":                               166,
+	"Random port on start":               47,
+	"Ratio":                              108,
+	"Ratio limit":                        66,
+	"Remove tracker":                     248,
+	"Rename to:":                         229,
+	"Resumed":                            261,
+	"Retrieving metadata":                6,
+	"Sat":                                101,
+	"Save":                               86,
+	"Schedule":                           94,
+	"Scheduled":                          60,
+	"Search":                             163,
+	"Search:":                            252,
+	"Seed queue":                         54,
+	"Seeding":                            64,
+	"Select category":                    244,
+	"Select dir":                         241,
+	"Sent to the running trango":         0,
+	"Session default":                    81,
+	"Session settings":                   87,
+	"Session statistics":                 105,
+	"Sessions":                           110,
+	"Set category for selected torrents": 232,
+	"Set host":                           119,
+	"Set password (prefer TRANGO_PASS, netrc or pass_command)": 131,
+	"Set port": 120,
+	"Set the interval for updating torrents information in seconds": 142,
+	"Set username":                          130,
+	"Settings saved":                        88,
+	"Show dialog when adding a new torrent": 134,
+	"Show full status names":                132,
+	"Size":                                  21,
+	"Skip stalled torrents":                 56,
+	"Sort":                                  171,
+	"Sort by":                               172,
+	"SortBy":                                167,
+	"Source":                                25,
+	"Space":                                 191,
+	"Speed":                                 32,
+	"Stalled after (minutes)":               57,
+	"Start added torrent":                   133,
+	"Start added torrents":                  73,
+	"Start yes/no":                          194,
+	"Status":                                155,
+	"Stop at ratio":                         65,
+	"Stop when idle":                        67,
+	"Stopped":                               149,
+	"Sun":                                   95,
+	"The flags must come before the torrents": 168,
+	"Thu":                   99,
+	"Time active":           111,
+	"To":                    62,
+	"Torrent already added": 15,
+	"Torrent not added":     197,
+	"Torrent settings":      89,
+	"Torrent was removed":   7,
+	"Torrents":              114,
+	"Total":                 113,
+	"Total Size":            258,
+	"Tracker URL:":          231,
+	"Trackers":              29,
+	"Tue":                   97,
+	"URL":                   236,
+	"Unknown profile":       16,
+	"Unknown sort order":    181,
+	"Unlimited":             82,
+	"Upload limit (kB/s)":   36,
+	"Uploaded":              106,
+	"Uploading":             263,
+	"Use alternative speed": 59,
+	"Use incomplete dir":    70,
+	"Web seeds":             30,
+	"Wed":                   98,
+	"Weekdays":              103,
+	"Weekend":               104,
+	"Yes":                   226,
+	"You need transmission-daemon version 3.00 or later for the categories support.": 170,
+	"add a torrent file, magnet link or URL":                                         221,
+	"alternative speed and schedule":                                                 217,
+	"cancel selection":                                                               209,
+	"create a new category for selected torrent(s)":                                  210,
+	"d":                                   116,
+	"daemon":                              182,
+	"h":                                   117,
+	"kB/s":                                93,
+	"local":                               183,
+	"m":                                   118,
+	"move to the top/bottom of the queue": 223,
+	"move up/down in the queue":           222,
+	"no":                                  17,
+	"open comment url":                    211,
+	"open download dir":                   212,
+	"or":                                  204,
+	"preview/open file(s)":                206,
+	"reannounce":                          202,
+	"remove torrent(s)":                   203,
+	"remove torrent(s) with data":         205,
+	"rename torrent":                      213,
+	"s":                                   269,
+	"select all":                          208,
+	"select/unselect":                     207,
+	"session settings":                    215,
+	"session statistics":                  220,
+	"start":                               199,
+	"start now, skipping the queue":       219,
+	"stop":                                200,
+	"switch daemon profile":               214,
+	"toggle alternative speed":            216,
+	"torrent limits and priority":         218,
+	"torrents":                            90,
+	"uTP":                                 44,
+	"verify":                              201,
+	"yes":                                 18,
+	"|   Size    |  Priority  |  Name ":   240,
+	"|  Uploading  | Downloading | Peers |  Done  |   Size    |": 158,
 }
 
-var enIndex = []uint32{ // 136 elements
+var enIndex = []uint32{ // 271 elements
 	// Entry 0 - 1F
-	0x00000000, 0x00000009, 0x00000012, 0x00000045,
-	0x00000081, 0x000000a0, 0x000000d7, 0x000000e4,
-	0x000000f1, 0x00000108, 0x0000011c, 0x00000158,
-	0x00000190, 0x000001ce, 0x000001e4, 0x000001e8,
-	0x000001f0, 0x000001f8, 0x00000203, 0x0000020c,
-	0x00000213, 0x0000021f, 0x00000227, 0x0000022f,
-	0x00000236, 0x0000023b, 0x0000023f, 0x0000027a,
-	0x00000287, 0x0000028c, 0x00000295, 0x0000029d,
+	0x00000000, 0x0000001b, 0x00000021, 0x0000002b,
+	0x00000035, 0x0000003c, 0x0000004e, 0x00000062,
+	0x00000076, 0x0000008f, 0x00000095, 0x0000009a,
+	0x000000a5, 0x000000b1, 0x000000b9, 0x000000c0,
+	0x000000d6, 0x000000e6, 0x000000e9, 0x000000ed,
+	0x000000f2, 0x000000fc, 0x00000101, 0x0000010c,
+	0x00000113, 0x0000011b, 0x00000122, 0x0000012a,
+	0x00000135, 0x00000140, 0x00000149, 0x00000153,
 	// Entry 20 - 3F
-	0x000002a6, 0x000002ac, 0x000002b3, 0x000002bb,
-	0x000002c0, 0x000002c5, 0x000002cc, 0x0000031b,
-	0x00000321, 0x00000326, 0x0000032e, 0x00000340,
-	0x0000034c, 0x0000035c, 0x00000368, 0x0000036d,
-	0x0000038b, 0x0000039d, 0x000003a4, 0x000003ae,
-	0x000003be, 0x000003ca, 0x000003de, 0x000003e1,
-	0x000003e5, 0x000003ef, 0x000003f5, 0x000003f9,
-	0x00000408, 0x00000415, 0x0000041a, 0x00000430,
+	0x00000159, 0x0000015f, 0x00000174, 0x0000018a,
+	0x0000019d, 0x000001b1, 0x000001b7, 0x000001c9,
+	0x000001e0, 0x000001eb, 0x000001ef, 0x000001f3,
+	0x00000208, 0x0000020c, 0x00000214, 0x0000021e,
+	0x00000233, 0x00000243, 0x0000024d, 0x0000025b,
+	0x00000261, 0x00000270, 0x00000281, 0x0000028c,
+	0x00000299, 0x000002af, 0x000002c7, 0x000002d9,
+	0x000002ef, 0x000002f9, 0x000002fe, 0x00000301,
 	// Entry 40 - 5F
-	0x00000438, 0x0000043e, 0x00000443, 0x0000044a,
-	0x00000455, 0x00000467, 0x0000046a, 0x00000486,
-	0x0000049b, 0x000004ab, 0x000004b6, 0x000004c7,
-	0x000004f5, 0x00000506, 0x00000518, 0x00000527,
-	0x00000546, 0x0000054b, 0x0000054e, 0x00000552,
-	0x0000056f, 0x00000578, 0x00000583, 0x00000597,
-	0x000005a4, 0x000005c7, 0x000005da, 0x000005e5,
-	0x000005ec, 0x000005f0, 0x00000610, 0x00000619,
+	0x00000309, 0x00000311, 0x0000031f, 0x0000032b,
+	0x0000033a, 0x0000034f, 0x0000035c, 0x0000036f,
+	0x0000037e, 0x0000039f, 0x000003b4, 0x000003d0,
+	0x000003e5, 0x000003f8, 0x000003fc, 0x00000403,
+	0x00000408, 0x00000413, 0x00000423, 0x0000042d,
+	0x00000438, 0x0000043d, 0x00000448, 0x0000044d,
+	0x0000045e, 0x0000046d, 0x0000047e, 0x00000487,
+	0x00000495, 0x0000049f, 0x000004a4, 0x000004ad,
 	// Entry 60 - 7F
-	0x00000622, 0x00000630, 0x00000656, 0x00000661,
-	0x0000066a, 0x00000676, 0x00000686, 0x00000693,
-	0x0000069c, 0x000006ae, 0x000006bd, 0x000006fa,
-	0x0000070c, 0x00000711, 0x00000719, 0x00000726,
-	0x0000072b, 0x00000730, 0x00000739, 0x00000741,
-	0x0000074a, 0x00000750, 0x00000758, 0x00000760,
-	0x00000766, 0x00000771, 0x00000778, 0x00000780,
-	0x00000787, 0x00000791, 0x00000795, 0x00000799,
+	0x000004b1, 0x000004b5, 0x000004b9, 0x000004bd,
+	0x000004c1, 0x000004c5, 0x000004c9, 0x000004d3,
+	0x000004dc, 0x000004e4, 0x000004f7, 0x00000500,
+	0x0000050b, 0x00000511, 0x0000051d, 0x00000526,
+	0x00000532, 0x00000542, 0x00000548, 0x00000551,
+	0x0000055f, 0x00000561, 0x00000563, 0x00000565,
+	0x0000056e, 0x00000577, 0x000005cf, 0x00000610,
+	0x00000642, 0x00000670, 0x00000699, 0x000006cc,
 	// Entry 80 - 9F
-	0x0000079d, 0x0000079f, 0x000007a4, 0x000007a9,
-	0x000007ab, 0x000007ad, 0x000007af, 0x000007b1,
-} // Size: 568 bytes
+	0x00000708, 0x00000767, 0x0000079e, 0x000007ab,
+	0x000007e4, 0x000007fb, 0x0000080f, 0x00000835,
+	0x00000879, 0x000008a7, 0x00000916, 0x00000967,
+	0x000009ae, 0x000009c2, 0x000009fa, 0x00000a38,
+	0x00000a8a, 0x00000aa0, 0x00000ac9, 0x00000afd,
+	0x00000b01, 0x00000b09, 0x00000b11, 0x00000b1c,
+	0x00000b25, 0x00000b2c, 0x00000b38, 0x00000b40,
+	0x00000b47, 0x00000b4c, 0x00000b50, 0x00000b8b,
+	// Entry A0 - BF
+	0x00000b98, 0x00000b9d, 0x00000ba6, 0x00000bae,
+	0x00000bb5, 0x00000bbd, 0x00000bc2, 0x00000bc7,
+	0x00000bce, 0x00000bf6, 0x00000c02, 0x00000c51,
+	0x00000c56, 0x00000c5e, 0x00000c70, 0x00000c7c,
+	0x00000c8c, 0x00000c98, 0x00000ca5, 0x00000cc4,
+	0x00000ccc, 0x00000cd5, 0x00000ce8, 0x00000cef,
+	0x00000cf5, 0x00000cfa, 0x00000d04, 0x00000d22,
+	0x00000d34, 0x00000d44, 0x00000d58, 0x00000d62,
+	// Entry C0 - DF
+	0x00000d68, 0x00000d6c, 0x00000d7b, 0x00000d88,
+	0x00000d8d, 0x00000d96, 0x00000da8, 0x00000db0,
+	0x00000db6, 0x00000dbb, 0x00000dc2, 0x00000dcd,
+	0x00000ddf, 0x00000de2, 0x00000dfe, 0x00000e13,
+	0x00000e23, 0x00000e2e, 0x00000e3f, 0x00000e6d,
+	0x00000e7e, 0x00000e90, 0x00000e9f, 0x00000eb5,
+	0x00000ec6, 0x00000edf, 0x00000efe, 0x00000f1a,
+	0x00000f38, 0x00000f4b, 0x00000f72, 0x00000f8c,
+	// Entry E0 - FF
+	0x00000fb0, 0x00000fcf, 0x00000fd2, 0x00000fd6,
+	0x00000ff3, 0x00000ffc, 0x00001007, 0x0000101b,
+	0x00001028, 0x0000104b, 0x0000105e, 0x00001069,
+	0x00001070, 0x00001074, 0x00001094, 0x0000109d,
+	0x000010ab, 0x000010d1, 0x000010dc, 0x000010e5,
+	0x000010f1, 0x00001101, 0x0000110e, 0x00001117,
+	0x00001129, 0x00001138, 0x00001175, 0x00001187,
+	0x0000118c, 0x00001194, 0x000011a1, 0x000011a6,
+	// Entry 100 - 11F
+	0x000011af, 0x000011b7, 0x000011bf, 0x000011ca,
+	0x000011d1, 0x000011f2, 0x000011fa, 0x00001201,
+	0x0000120b, 0x0000120f, 0x00001213, 0x00001217,
+	0x00001219, 0x0000121e, 0x00001220,
+} // Size: 1108 bytes
 
-const enData string = "" + // Size: 1969 bytes
-	"\x02Set host\x02Set port\x02<path>  Set download dir when adding a new t" +
-	"orrent\x02<name1,name2,...>  Set categories when adding a new torrent" +
-	"\x02<filename-or-URL>  Add torrent\x02<0,1,2,3,...> Mark files for downl" +
-	"oad by index numbers\x02Set username\x02Set password\x02Show full status" +
-	" names\x02Start added torrent\x02Show dialog when adding a new torrent f" +
-	"ile (not url/magnet)\x02Print tracker URLs of a torrent file to standard" +
-	" output\x02Set the interval for updating torrents information in seconds" +
-	"\x02Print current version\x02All\x02Default\x02Stopped\x02Check wait\x02" +
-	"Checking\x02Queued\x02Downloading\x02Seeding\x02Errored\x02Status\x02Don" +
-	"e\x02ETA\x02|  Uploading  | Downloading | Peers |  Done  |   Size    |" +
-	"\x04\x03   \x01 \x05\x02Name\x02Quit\x02Category\x02General\x02Trackers" +
-	"\x02Peers\x02Search\x02Content\x02Move\x02Help\x02SortBy\x02You need tra" +
-	"nsmission-daemon version 3.00 or later for the categories support.\x02Cl" +
-	"ose\x02Sort\x02Sort by\x04\x03   \x00\x0b\x02Added Date\x04\x03   \x00" +
-	"\x05\x02Name\x04\x03   \x00\x09\x02Progress\x04\x03   \x00\x05\x02Size" +
-	"\x02Free\x02Enter a new category name(s):\x02Enter a new path:\x02Cancel" +
-	"\x04\x01 \x00\x05\x02Path\x04\x01 \x01 \x0a\x02Category:\x02Add torrent" +
-	"\x04\x01 \x00\x0f\x02Start torrent:\x02no\x02yes\x04\x01 \x00\x05\x02Siz" +
-	"e\x02Space\x02Get\x02(Un)expand dir\x02Start yes/no\x02Path\x02Torrent a" +
-	"lready added\x02Hotkeys\x02start\x02stop\x02verify\x02reannounce\x02remo" +
-	"ve torrent(s)\x02or\x02remove torrent(s) with data\x02preview/open file(" +
-	"s)\x02select/unselect\x02select all\x02cancel selection\x02create a new " +
-	"category for selected torrent(s)\x02open comment url\x02open download di" +
-	"r\x02rename torrent\x04\x02  \x01 \x18\x02Done  |  Size   |  Name\x02Ope" +
-	"n\x02No\x02Yes\x02Do you really want to delete\x02Move to:\x02Rename to:" +
-	"\x02Enter announce URL:\x02Tracker URL:\x02Set category for selected tor" +
-	"rents\x02Filter by category\x02Categories\x02Active\x02URL\x04\x02  \x01" +
-	" \x19\x02| Peers | Seeds | Status\x02Priority\x02Next dir\x02Next root d" +
-	"ir\x04\x00\x01 !\x02|   Size    |  Priority  |  Name\x02Select dir\x02Ne" +
-	"w path\x02Directories\x02Select category\x02New category\x02Edit URL\x02" +
-	"Add a new tracker\x02Remove tracker\x04\x01 \x008\x02|  Done  | Download" +
-	"ing | Uploading |   Flags   | Client\x02(Un)pause updates\x02Next\x02Sea" +
-	"rch:\x02General Info\x02Name\x02Hash\x02Location\x02Comment\x02Uploaded" +
-	"\x02Ratio\x02Created\x02Creator\x02Added\x02Total Size\x02Errors\x02Resu" +
-	"med\x02Paused\x02Uploading\x02GiB\x02MiB\x02KiB\x02B\x02MB/s\x02kB/s\x02" +
-	"d\x02h\x02m\x02s"
+const enData string = "" + // Size: 4640 bytes
+	"\x02Sent to the running trango\x02Added\x02Duplicate\x02Cancelled\x02Fai" +
+	"led\x02No matching files\x02Retrieving metadata\x02Torrent was removed" +
+	"\x02Magnet link, URL or path\x02Close\x02Open\x02Parent dir\x02Add torre" +
+	"nt\x02Loading\x02Cancel\x02Torrent already added\x02Unknown profile\x02n" +
+	"o\x02yes\x02Name\x02Info hash\x02Size\x02Piece size\x02Pieces\x02Private" +
+	"\x02Source\x02Comment\x02Created by\x02Created on\x02Trackers\x02Web see" +
+	"ds\x02Files\x02Speed\x02Limit download speed\x02Download limit (kB/s)" +
+	"\x02Limit upload speed\x02Upload limit (kB/s)\x02Peers\x02Global peer li" +
+	"mit\x02Peer limit per torrent\x02Encryption\x02DHT\x02PEX\x02Local peer " +
+	"discovery\x02uTP\x02Network\x02Peer port\x02Random port on start\x02Port" +
+	" forwarding\x02Blocklist\x02Blocklist URL\x02Queue\x02Download queue\x02" +
+	"Active downloads\x02Seed queue\x02Active seeds\x02Skip stalled torrents" +
+	"\x02Stalled after (minutes)\x02Alternative speed\x02Use alternative spee" +
+	"d\x02Scheduled\x02From\x02To\x02On days\x02Seeding\x02Stop at ratio\x02R" +
+	"atio limit\x02Stop when idle\x02Idle limit (minutes)\x02Download dir\x02" +
+	"Use incomplete dir\x02Incomplete dir\x02Append .part to incomplete files" +
+	"\x02Start added torrents\x02Delete added .torrent files\x02Honor session" +
+	" limits\x02Bandwidth priority\x02Low\x02Normal\x02High\x02Peer limit\x02" +
+	"Session default\x02Unlimited\x02Idle limit\x02Edit\x02Next field\x02Save" +
+	"\x02Session settings\x02Settings saved\x02Torrent settings\x02torrents" +
+	"\x02Invalid value\x02Alt speed\x02kB/s\x02Schedule\x02Sun\x02Mon\x02Tue" +
+	"\x02Wed\x02Thu\x02Fri\x02Sat\x02Every day\x02Weekdays\x02Weekend\x02Sess" +
+	"ion statistics\x02Uploaded\x02Downloaded\x02Ratio\x02Files added\x02Sess" +
+	"ions\x02Time active\x02Current session\x02Total\x02Torrents\x02Download " +
+	"dirs\x02d\x02h\x02m\x02Set host\x02Set port\x02<URL>  Set full RPC URL, " +
+	"e.g. https://host/transmission/rpc (overrides -host and -port)\x02<file>" +
+	"  Verify the daemon with CA certificates from the PEM file\x02<file>  Cl" +
+	"ient certificate for TLS authentication\x02<file>  Private key of the cl" +
+	"ient certificate\x02Do not verify the daemon TLS certificate\x02<path>  " +
+	"Set download dir when adding a new torrent\x02<name1,name2,...>  Set cat" +
+	"egories when adding a new torrent\x02<filename-or-URL>  Add torrent, mor" +
+	"e can follow as arguments, - reads them from standard input\x02<0,1,2,3," +
+	"...> Mark files for download by index numbers\x02Set username\x02Set pas" +
+	"sword (prefer TRANGO_PASS, netrc or pass_command)\x02Show full status na" +
+	"mes\x02Start added torrent\x02Show dialog when adding a new torrent\x02<" +
+	"low|normal|high>  Set bandwidth priority when adding a new torrent\x02<n" +
+	">  Set peer limit when adding a new torrent\x02<auto|always|never>  Send" +
+	" the contents of a torrent file instead of its path, auto does it for a " +
+	"remote daemon\x02<dir>  Start dir of the file browser for adding torrent" +
+	"s, ~/Downloads by default\x02<file>  Print the contents of a torrent fil" +
+	"e, the daemon is not needed\x02Print -info as JSON\x02Print tracker URLs" +
+	" of a torrent file to standard output\x02Set the interval for updating t" +
+	"orrents information in seconds\x02<n>  Reload all torrents every n updat" +
+	"es, only changed ones are fetched otherwise\x02Print current version\x02" +
+	"<filename>  Use an alternate config file\x02<name>  Connect with a profi" +
+	"le from the config file\x02All\x02Default\x02Stopped\x02Check wait\x02Ch" +
+	"ecking\x02Queued\x02Downloading\x02Errored\x02Status\x02Done\x02ETA\x02|" +
+	"  Uploading  | Downloading | Peers |  Done  |   Size    |\x04\x03   \x01" +
+	" \x05\x02Name\x02Help\x02Category\x02General\x02Search\x02Content\x02Mov" +
+	"e\x02Quit\x02SortBy\x02The flags must come before the torrents\x02Invali" +
+	"d URL\x02You need transmission-daemon version 3.00 or later for the cate" +
+	"gories support.\x02Sort\x02Sort by\x04\x03   \x00\x0b\x02Added Date\x04" +
+	"\x03   \x00\x05\x02Name\x04\x03   \x00\x09\x02Progress\x04\x03   \x00" +
+	"\x05\x02Size\x04\x03   \x00\x06\x02Queue\x02No profiles in the config fi" +
+	"le\x02Connect\x02Profiles\x02Unknown sort order\x02daemon\x02local\x02Fr" +
+	"ee\x04\x01 \x00\x05\x02Path\x02Enter a new category name(s):\x02Enter a " +
+	"new path:\x04\x01 \x01 \x0a\x02Category:\x04\x01 \x00\x0f\x02Start torre" +
+	"nt:\x04\x01 \x00\x05\x02Size\x02Space\x02Get\x02(Un)expand dir\x02Start " +
+	"yes/no\x02Path\x02Priority\x02Torrent not added\x02Hotkeys\x02start\x02s" +
+	"top\x02verify\x02reannounce\x02remove torrent(s)\x02or\x02remove torrent" +
+	"(s) with data\x02preview/open file(s)\x02select/unselect\x02select all" +
+	"\x02cancel selection\x02create a new category for selected torrent(s)" +
+	"\x02open comment url\x02open download dir\x02rename torrent\x02switch da" +
+	"emon profile\x02session settings\x02toggle alternative speed\x02alternat" +
+	"ive speed and schedule\x02torrent limits and priority\x02start now, skip" +
+	"ping the queue\x02session statistics\x02add a torrent file, magnet link " +
+	"or URL\x02move up/down in the queue\x02move to the top/bottom of the que" +
+	"ue\x04\x02  \x01 \x18\x02Done  |  Size   |  Name\x02No\x02Yes\x02Do you " +
+	"really want to delete\x02Move to:\x02Rename to:\x02Enter announce URL:" +
+	"\x02Tracker URL:\x02Set category for selected torrents\x02Filter by cate" +
+	"gory\x02Categories\x02Active\x02URL\x04\x02  \x01 \x19\x02| Peers | Seed" +
+	"s | Status\x02Next dir\x02Next root dir\x04\x00\x01 !\x02|   Size    |  " +
+	"Priority  |  Name\x02Select dir\x02New path\x02Directories\x02Select cat" +
+	"egory\x02New category\x02Edit URL\x02Add a new tracker\x02Remove tracker" +
+	"\x04\x01 \x008\x02|  Done  | Downloading | Uploading |   Flags   | Clien" +
+	"t\x02(Un)pause updates\x02Next\x02Search:\x02General Info\x02Hash\x02Loc" +
+	"ation\x02Created\x02Creator\x02Total Size\x02Errors\x02Disconnected, ret" +
+	"rying in %[1]ds\x02Resumed\x02Paused\x02Uploading\x02GiB\x02MiB\x02KiB" +
+	"\x02B\x02MB/s\x02s"
 
-var ruIndex = []uint32{ // 136 elements
+var ruIndex = []uint32{ // 271 elements
 	// Entry 0 - 1F
-	0x00000000, 0x0000001e, 0x0000003c, 0x000000aa,
-	0x00000116, 0x00000156, 0x000001bd, 0x000001f2,
-	0x00000214, 0x00000252, 0x0000028d, 0x00000304,
-	0x00000370, 0x000003ea, 0x00000408, 0x0000040f,
-	0x00000427, 0x0000043c, 0x00000456, 0x0000046d,
-	0x0000047f, 0x00000490, 0x000004a3, 0x000004b5,
-	0x000004c2, 0x000004cf, 0x000004da, 0x00000533,
-	0x00000542, 0x0000054d, 0x00000560, 0x0000056b,
+	0x00000000, 0x0000002d, 0x0000004b, 0x0000005c,
+	0x0000006d, 0x0000007a, 0x000000a3, 0x000000cb,
+	0x000000ee, 0x00000117, 0x00000126, 0x00000135,
+	0x0000015d, 0x0000017d, 0x0000018e, 0x0000019b,
+	0x000001c2, 0x000001e8, 0x000001ef, 0x000001f4,
+	0x000001fb, 0x00000207, 0x00000214, 0x0000022c,
+	0x00000239, 0x0000024c, 0x0000025d, 0x00000274,
+	0x00000296, 0x000002b0, 0x000002bf, 0x000002cf,
 	// Entry 20 - 3F
-	0x0000057a, 0x00000583, 0x0000058e, 0x00000599,
-	0x000005b0, 0x000005bd, 0x000005d2, 0x0000064d,
-	0x0000065c, 0x00000671, 0x0000068d, 0x000006b2,
-	0x000006c0, 0x000006d8, 0x000006ec, 0x000006fd,
-	0x00000735, 0x00000758, 0x00000765, 0x00000773,
-	0x0000078d, 0x000007ad, 0x000007d7, 0x000007de,
-	0x000007e3, 0x000007f5, 0x00000802, 0x00000813,
-	0x00000833, 0x00000854, 0x0000085d, 0x00000884,
+	0x000002da, 0x000002eb, 0x00000322, 0x00000348,
+	0x0000037b, 0x0000039d, 0x000003a6, 0x000003c7,
+	0x000003f1, 0x00000406, 0x0000040a, 0x0000040e,
+	0x00000437, 0x0000043b, 0x00000444, 0x0000045f,
+	0x00000491, 0x000004ab, 0x000004bd, 0x000004d5,
+	0x000004e4, 0x00000504, 0x00000526, 0x00000542,
+	0x00000560, 0x00000597, 0x000005c0, 0x000005ee,
+	0x00000635, 0x0000064f, 0x00000652, 0x00000657,
 	// Entry 40 - 5F
-	0x000008a2, 0x000008b7, 0x000008cc, 0x000008df,
-	0x000008fe, 0x00000920, 0x00000927, 0x00000961,
-	0x00000996, 0x000009c5, 0x000009dd, 0x00000a01,
-	0x00000a5b, 0x00000a9e, 0x00000acd, 0x00000af7,
-	0x00000b24, 0x00000b33, 0x00000b3a, 0x00000b3f,
-	0x00000b7b, 0x00000b96, 0x00000bb5, 0x00000bd8,
-	0x00000bec, 0x00000c43, 0x00000c72, 0x00000c85,
-	0x00000c94, 0x00000c9f, 0x00000ccd, 0x00000ce0,
+	0x00000665, 0x00000678, 0x000006a5, 0x000006c1,
+	0x000006ec, 0x00000713, 0x00000733, 0x0000077d,
+	0x000007ae, 0x000007f2, 0x0000082d, 0x00000867,
+	0x0000089c, 0x000008af, 0x000008bc, 0x000008cb,
+	0x000008da, 0x000008f0, 0x00000907, 0x00000925,
+	0x0000093f, 0x00000950, 0x0000096c, 0x0000097f,
+	0x0000099f, 0x000009c5, 0x000009e9, 0x000009fc,
+	0x00000a1e, 0x00000a39, 0x00000a41, 0x00000a56,
 	// Entry 60 - 7F
-	0x00000d02, 0x00000d35, 0x00000d6c, 0x00000d8a,
-	0x00000d9e, 0x00000db3, 0x00000dd5, 0x00000df3,
-	0x00000e12, 0x00000e3b, 0x00000e57, 0x00000eb3,
-	0x00000f07, 0x00000f1a, 0x00000f26, 0x00000f46,
-	0x00000f4d, 0x00000f54, 0x00000f6d, 0x00000f84,
-	0x00000f91, 0x00000fa0, 0x00000fba, 0x00000fca,
-	0x00000fe8, 0x00001000, 0x0000100d, 0x00001026,
-	0x0000103d, 0x0000104a, 0x00001051, 0x00001058,
+	0x00000a5b, 0x00000a60, 0x00000a65, 0x00000a6a,
+	0x00000a6f, 0x00000a74, 0x00000a79, 0x00000a8f,
+	0x00000a9a, 0x00000aab, 0x00000acd, 0x00000ada,
+	0x00000aed, 0x00000afc, 0x00000b1c, 0x00000b2b,
+	0x00000b43, 0x00000b5d, 0x00000b68, 0x00000b79,
+	0x00000b9b, 0x00000b9e, 0x00000ba1, 0x00000ba4,
+	0x00000bc2, 0x00000be0, 0x00000c63, 0x00000cc2,
+	0x00000d20, 0x00000d74, 0x00000db2, 0x00000e20,
 	// Entry 80 - 9F
-	0x0000105f, 0x00001062, 0x0000106a, 0x00001072,
-	0x00001075, 0x00001078, 0x0000107b, 0x0000107e,
-} // Size: 568 bytes
+	0x00000e8c, 0x00000f40, 0x00000fa7, 0x00000fdc,
+	0x00001032, 0x00001070, 0x000010ab, 0x00001107,
+	0x00001174, 0x000011d6, 0x00001288, 0x0000132a,
+	0x00001392, 0x000013be, 0x0000142a, 0x000014a4,
+	0x00001526, 0x00001544, 0x00001599, 0x000015f1,
+	0x000015f8, 0x00001610, 0x00001625, 0x0000163f,
+	0x00001656, 0x00001668, 0x00001679, 0x0000168b,
+	0x00001698, 0x000016a5, 0x000016b0, 0x00001709,
+	// Entry A0 - BF
+	0x00001718, 0x00001725, 0x00001738, 0x00001743,
+	0x0000174e, 0x00001759, 0x00001770, 0x0000177b,
+	0x00001790, 0x000017d1, 0x000017e6, 0x00001861,
+	0x00001876, 0x00001892, 0x000018b7, 0x000018c5,
+	0x000018dd, 0x000018f1, 0x00001907, 0x0000193e,
+	0x00001957, 0x00001966, 0x000019a1, 0x000019b5,
+	0x000019c6, 0x000019d7, 0x000019e5, 0x00001a1d,
+	0x00001a40, 0x00001a5a, 0x00001a84, 0x00001a96,
+	// Entry C0 - DF
+	0x00001aa3, 0x00001ab4, 0x00001ad4, 0x00001af5,
+	0x00001afe, 0x00001b11, 0x00001b36, 0x00001b54,
+	0x00001b69, 0x00001b7e, 0x00001b91, 0x00001bb0,
+	0x00001bd2, 0x00001bd9, 0x00001c13, 0x00001c48,
+	0x00001c77, 0x00001c8f, 0x00001cb3, 0x00001d0d,
+	0x00001d50, 0x00001d7f, 0x00001da9, 0x00001ddc,
+	0x00001dfc, 0x00001e41, 0x00001e87, 0x00001ec5,
+	0x00001f00, 0x00001f22, 0x00001f6b, 0x00001fa8,
+	// Entry E0 - FF
+	0x00001fe9, 0x00002016, 0x0000201d, 0x00002022,
+	0x0000205e, 0x00002079, 0x00002098, 0x000020bb,
+	0x000020cf, 0x00002126, 0x00002155, 0x00002168,
+	0x00002177, 0x00002182, 0x000021b0, 0x000021d2,
+	0x00002205, 0x0000223c, 0x0000225a, 0x0000226e,
+	0x00002283, 0x000022a5, 0x000022c3, 0x000022e2,
+	0x0000230b, 0x00002327, 0x00002383, 0x000023d7,
+	0x000023ea, 0x000023f6, 0x00002416, 0x0000241d,
+	// Entry 100 - 11F
+	0x00002436, 0x00002450, 0x00002460, 0x00002478,
+	0x00002485, 0x000024c2, 0x000024db, 0x000024f2,
+	0x000024ff, 0x00002506, 0x0000250d, 0x00002514,
+	0x00002517, 0x0000251f, 0x00002522,
+} // Size: 1108 bytes
 
-const ruData string = "" + // Size: 4222 bytes
-	"\x02Установить хост\x02Установить порт\x02<путь>  Установить каталог заг" +
-	"рузки при добавлении торрента\x02<имя1,имя2,...>  Установить категории " +
-	"при добавлении торрента\x02<имя_файла или URL>  Добавить торрент\x02<0," +
-	"1,2,3,...> Отметить файлы для загрузки по номерам индексов\x02Установить" +
-	" имя пользователя\x02Установить пароль\x02Показывать полные имена статус" +
-	"ов\x02Стартовать добавленный торрент\x02Показывать диалог при добавлени" +
-	"и нового торрент-файла (не url/magnet)\x02Вывести адреса трекеров торре" +
-	"нт-файла в стандартный вывод\x02Установить интервал обновления информац" +
-	"ии о торрентах в секундах\x02Показать версию\x02Все\x02По умолчанию\x02" +
-	"Остановлен\x02Ждёт проверки\x02Проверяется\x02В очереди\x02Загрузка\x02" +
-	"Раздаётся\x02С ошибкой\x02Статус\x02Готово\x02Время\x02|   Отдача    | " +
-	"  Загрузка  | Пиры  | Готово |  Размер   |\x04\x03   \x01 \x07\x02Имя" +
-	"\x02Выход\x02Категория\x02Общие\x02Трекеры\x02Пиры\x02Поиск\x02Файлы\x02" +
-	"Переместить\x02Помощь\x02Сортировка\x02Для поддержки категорий требуетс" +
-	"я transmission-daemon версии 3.00 или больше.\x02Закрыть\x02Сортировка" +
-	"\x02Сортировать по\x04\x03   \x00\x1e\x02Дата добавления\x04\x03   \x00" +
-	"\x07\x02Имя\x04\x03   \x00\x11\x02Прогресс\x04\x03   \x00\x0d\x02Размер" +
-	"\x02Свободно\x02Введите имя новой категории(й)\x02Введите новый путь\x02" +
-	"Отмена\x04\x01 \x00\x09\x02Путь\x04\x01 \x01 \x14\x02Категория:\x02Доба" +
-	"вить торрент\x04\x01 \x00%\x02Стартовать торрент:\x02нет\x02да\x04\x01 " +
-	"\x00\x0d\x02Размер\x02Пробел\x02Получить\x02Свернуть каталог\x02Стартова" +
-	"ть да/нет\x02Путь\x02Торрент уже добавлен\x02Горячие клавиши\x02стартов" +
-	"ать\x02остановить\x02проверить\x02реаннонсировать\x02удалить торрент(ы)" +
-	"\x02или\x02удалить торрент(ы) с содержимым\x02предпросмотр/открыть файл(" +
-	"ы)\x02выделить/снять выделение\x02выделить все\x02отменить выделение" +
-	"\x02создать новую категорию для выбранных торрентов\x02открыть url из ко" +
-	"мментария к торренту\x02открыть каталог загрузки\x02переименовать торре" +
-	"нт\x04\x02  \x01 &\x02Готово|  Размер |  Имя\x02Открыть\x02Нет\x02Да" +
-	"\x02Вы действительно хотите удалить\x02Переместить в:\x02Переименовать в" +
-	":\x02Введите URL трекера:\x02URL трекера:\x02Установить категорию для вы" +
-	"деленных торрентов\x02Фильтровать по категории\x02Категории\x02Активны" +
-	"\x02Адрес\x04\x02  \x01 '\x02| Пиры  | Сиды  | Статус\x02Приоритет\x02Сл" +
-	"едующий каталог\x02Следующий корневой каталог\x04\x00\x01 2\x02|   Разм" +
-	"ер  |  Приоритет |  Имя\x02Выбрать каталог\x02Новый путь\x02Директории" +
-	"\x02Выбрать категорию\x02Новая категория\x02Редактировать URL\x02Добавит" +
-	"ь новый трекер\x02Удалить трекер\x04\x01 \x00W\x02| Готово |  Загрузка " +
-	"  |  Отдача   |   Флаги   | Клиент\x02Приостановить/возобновить обновлен" +
-	"ия списка\x02Следующий\x02Поиск:\x02Общая информация\x02Имя\x02Хэш\x02Р" +
-	"асположение\x02Комментарий\x02Отдано\x02Рейтинг\x02Дата создания\x02Соз" +
-	"дан в\x02Дата добавления\x02Общий размер\x02Ошибки\x02Возобновлены\x02О" +
-	"становлены\x02Отдача\x02ГиБ\x02МиБ\x02КиБ\x02Б\x02МБ/с\x02кБ/с\x02д\x02" +
-	"ч\x02м\x02с"
+const ruData string = "" + // Size: 9506 bytes
+	"\x02Передан запущенному trango\x02Дата добавления\x02Дубликат\x02Отменен" +
+	"о\x02Ошибка\x02Нет подходящих файлов\x02Получение метаданных\x02Торрент" +
+	" был удалён\x02Magnet-ссылка, URL или путь\x02Закрыть\x02Открыть\x02Роди" +
+	"тельский каталог\x02Добавить торрент\x02Загрузка\x02Отмена\x02Торрент у" +
+	"же добавлен\x02Неизвестный профиль\x02нет\x02да\x02Имя\x02Info-хеш\x02Р" +
+	"азмер\x02Размер части\x02Частей\x02Приватный\x02Источник\x02Комментарий" +
+	"\x02Создан программой\x02Дата создания\x02Трекеры\x02Веб-сиды\x02Файлы" +
+	"\x02Скорость\x02Ограничить скорость загрузки\x02Лимит загрузки (кБ/с)" +
+	"\x02Ограничить скорость отдачи\x02Лимит отдачи (кБ/с)\x02Пиры\x02Общий л" +
+	"имит пиров\x02Лимит пиров на торрент\x02Шифрование\x02DHT\x02PEX\x02Пои" +
+	"ск локальных пиров\x02uTP\x02Сеть\x02Порт для пиров\x02Случайный порт п" +
+	"ри запуске\x02Проброс порта\x02Блок-лист\x02URL блок-листа\x02Очередь" +
+	"\x02Очередь загрузок\x02Активных загрузок\x02Очередь раздач\x02Активных " +
+	"раздач\x02Пропускать зависшие торренты\x02Зависший через (минут)\x02Аль" +
+	"тернативная скорость\x02Использовать альтернативную скорость\x02По расп" +
+	"исанию\x02С\x02До\x02По дням\x02Раздаётся\x02Остановить при рейтинге" +
+	"\x02Лимит рейтинга\x02Остановить при простое\x02Лимит простоя (минут)" +
+	"\x02Каталог загрузки\x02Использовать каталог для незавершённых\x02Катало" +
+	"г для незавершённых\x02Добавлять .part к незавершённым файлам\x02Запуск" +
+	"ать добавленные торренты\x02Удалять добавленные .torrent файлы\x02Учиты" +
+	"вать общие ограничения\x02Приоритет\x02Низкий\x02Обычный\x02Высокий\x02" +
+	"Лимит пиров\x02Как в сеансе\x02Без ограничений\x02Лимит простоя\x02Изме" +
+	"нить\x02Следующее поле\x02Сохранить\x02Настройки сессии\x02Настройки со" +
+	"хранены\x02Настройки торрента\x02торрентов\x02Неверное значение\x02Альт" +
+	". скорость\x02кБ/с\x02Расписание\x02Вс\x02Пн\x02Вт\x02Ср\x02Чт\x02Пт\x02" +
+	"Сб\x02Каждый день\x02Будни\x02Выходные\x02Статистика сеанса\x02Отдано" +
+	"\x02Загружено\x02Рейтинг\x02Добавлено файлов\x02Сеансов\x02Время работы" +
+	"\x02Текущий сеанс\x02Всего\x02Торренты\x02Каталоги загрузки\x02д\x02ч" +
+	"\x02м\x02Установить хост\x02Установить порт\x02<URL>  Установить полный " +
+	"URL RPC, например https://host/transmission/rpc (заменяет -host и -port)" +
+	"\x02<файл>  Проверять демон по сертификатам CA из PEM файла\x02<файл>  К" +
+	"лиентский сертификат для TLS аутентификации\x02<файл>  Закрытый ключ кл" +
+	"иентского сертификата\x02Не проверять TLS сертификат демона\x02<путь>  " +
+	"Установить каталог загрузки при добавлении торрента\x02<имя1,имя2,...> " +
+	" Установить категории при добавлении торрента\x02<файл-или-URL>  Добавит" +
+	"ь торрент, другие можно указать аргументами, - читает их со стандартног" +
+	"о ввода\x02<0,1,2,3,...> Отметить файлы для загрузки по номерам индексо" +
+	"в\x02Установить имя пользователя\x02Установить пароль (лучше TRANGO_PAS" +
+	"S, netrc или pass_command)\x02Показывать полные имена статусов\x02Старто" +
+	"вать добавленный торрент\x02Показывать диалог при добавлении нового тор" +
+	"рента\x02<low|normal|high>  Задать приоритет при добавлении нового торр" +
+	"ента\x02<n>  Задать лимит пиров при добавлении нового торрента\x02<auto" +
+	"|always|never>  Отправлять содержимое торрент-файла вместо пути, auto де" +
+	"лает это для удалённого демона\x02<каталог>  Начальный каталог обзора ф" +
+	"айлов для добавления торрентов, по умолчанию ~/Downloads\x02<файл>  Пок" +
+	"азать содержимое торрент-файла, демон не нужен\x02Вывести -info в форма" +
+	"те JSON\x02Вывести адреса трекеров торрент-файла в стандартный вывод" +
+	"\x02Установить интервал обновления информации о торрентах в секундах\x02" +
+	"<n>  Загружать все торренты каждые n обновлений, иначе только изменённые" +
+	"\x02Показать версию\x02<имя_файла>  Использовать другой файл настроек" +
+	"\x02<имя>  Подключиться с профилем из файла настроек\x02Все\x02По умолча" +
+	"нию\x02Остановлен\x02Ждёт проверки\x02Проверяется\x02В очереди\x02Загру" +
+	"зка\x02С ошибкой\x02Статус\x02Готово\x02Время\x02|   Отдача    |   Загр" +
+	"узка  | Пиры  | Готово |  Размер   |\x04\x03   \x01 \x07\x02Имя\x02Помо" +
+	"щь\x02Категория\x02Общие\x02Поиск\x02Файлы\x02Переместить\x02Выход\x02С" +
+	"ортировка\x02Флаги должны идти перед торрентами\x02Неверный URL\x02Для " +
+	"поддержки категорий требуется transmission-daemon версии 3.00 или больш" +
+	"е.\x02Сортировка\x02Сортировать по\x04\x03   \x00\x1e\x02Дата добавлени" +
+	"я\x04\x03   \x00\x07\x02Имя\x04\x03   \x00\x11\x02Прогресс\x04\x03   " +
+	"\x00\x0d\x02Размер\x04\x03   \x00\x0f\x02Очередь\x02В файле настроек нет" +
+	" профилей\x02Подключиться\x02Профили\x02Неизвестный порядок сортировки" +
+	"\x02на сервере\x02локально\x02Свободно\x04\x01 \x00\x09\x02Путь\x02Введи" +
+	"те имя новой категории(й)\x02Введите новый путь\x04\x01 \x01 \x14\x02Ка" +
+	"тегория:\x04\x01 \x00%\x02Стартовать торрент:\x04\x01 \x00\x0d\x02Разме" +
+	"р\x02Пробел\x02Получить\x02Свернуть каталог\x02Стартовать да/нет\x02Пут" +
+	"ь\x02Приоритет\x02Торрент не добавлен\x02Горячие клавиши\x02стартовать" +
+	"\x02остановить\x02проверить\x02реаннонсировать\x02удалить торрент(ы)\x02" +
+	"или\x02удалить торрент(ы) с содержимым\x02предпросмотр/открыть файл(ы)" +
+	"\x02выделить/снять выделение\x02выделить все\x02отменить выделение\x02со" +
+	"здать новую категорию для выбранных торрентов\x02открыть url из коммент" +
+	"ария к торренту\x02открыть каталог загрузки\x02переименовать торрент" +
+	"\x02переключить профиль демона\x02настройки сессии\x02переключить альтер" +
+	"нативную скорость\x02альтернативная скорость и расписание\x02ограничени" +
+	"я и приоритет торрента\x02запустить сейчас, минуя очередь\x02статистика" +
+	" сеанса\x02добавить торрент-файл, magnet-ссылку или URL\x02переместить в" +
+	"верх/вниз в очереди\x02переместить в начало/конец очереди\x04\x02  \x01" +
+	" &\x02Готово|  Размер |  Имя\x02Нет\x02Да\x02Вы действительно хотите уда" +
+	"лить\x02Переместить в:\x02Переименовать в:\x02Введите URL трекера:\x02U" +
+	"RL трекера:\x02Установить категорию для выделенных торрентов\x02Фильтров" +
+	"ать по категории\x02Категории\x02Активны\x02Адрес\x04\x02  \x01 '\x02| " +
+	"Пиры  | Сиды  | Статус\x02Следующий каталог\x02Следующий корневой катал" +
+	"ог\x04\x00\x01 2\x02|   Размер  |  Приоритет |  Имя\x02Выбрать каталог" +
+	"\x02Новый путь\x02Директории\x02Выбрать категорию\x02Новая категория\x02" +
+	"Редактировать URL\x02Добавить новый трекер\x02Удалить трекер\x04\x01 " +
+	"\x00W\x02| Готово |  Загрузка   |  Отдача   |   Флаги   | Клиент\x02Прио" +
+	"становить/возобновить обновления списка\x02Следующий\x02Поиск:\x02Общая" +
+	" информация\x02Хэш\x02Расположение\x02Дата создания\x02Создан в\x02Общий" +
+	" размер\x02Ошибки\x02Нет соединения, повтор через %[1]dс\x02Возобновлены" +
+	"\x02Остановлены\x02Отдача\x02ГиБ\x02МиБ\x02КиБ\x02Б\x02МБ/с\x02с"
 
-	// Total table size 7327 bytes (7KiB); checksum: 5334D3F3
+	// Total table size 16362 bytes (15KiB); checksum: CC5E97A1
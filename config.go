@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/takiz/trango/rpc"
+)
+
+const CONFIG_FILE = "config.json"
+
+// Connection settings of a daemon.
+type Profile struct {
+	Url  string `json:"url,omitempty"`
+	Host string `json:"host,omitempty"`
+	Port string `json:"port,omitempty"`
+	User string `json:"user,omitempty"`
+	Pass string `json:"pass,omitempty"`
+	// Command whose output is the password.
+	PassCommand string `json:"pass_command,omitempty"`
+	CACert      string `json:"cacert,omitempty"`
+	Cert        string `json:"cert,omitempty"`
+	Key         string `json:"key,omitempty"`
+	Insecure    bool   `json:"insecure,omitempty"`
+}
+
+// Options of the config file. The keys are the names of the command line
+// flags, which take precedence over the file values.
+type Config struct {
+	Profile
+	Update   int    `json:"update,omitempty"`
+	Resync   int    `json:"resync,omitempty"`
+	Ascii    bool   `json:"ascii,omitempty"`
+	Dir      string `json:"dir,omitempty"`
+	Category string `json:"category,omitempty"`
+	Start    bool   `json:"start,omitempty"`
+	Dialog   bool   `json:"dialog,omitempty"`
+	Metainfo string `json:"metainfo,omitempty"` // auto, always or never
+	Browse   string `json:"browse,omitempty"`   // Start dir of the file browser.
+	// UI preferences.
+	SortBy string `json:"sort,omitempty"` // date, name, progress, size or queue
+	// Named connections, the active one is selected with -profile.
+	ProfileName string              `json:"profile,omitempty"`
+	Profiles    map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// Overlay the connection settings of the named profile.
+func (cfg *Config) SelectProfile(name string) error {
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return errors.New(P("Unknown profile") + ": " + name)
+	}
+	if p.Url != "" {
+		// Don't mix with -host/-port of the file.
+		cfg.Host, cfg.Port = "", ""
+	}
+	cfg.Url = p.Url
+	cfg.CACert, cfg.Cert, cfg.Key = p.CACert, p.Cert, p.Key
+	cfg.Insecure = p.Insecure
+	if p.Host != "" {
+		cfg.Host = p.Host
+	}
+	if p.Port != "" {
+		cfg.Port = p.Port
+	}
+	if p.User != "" || p.Pass != "" || p.PassCommand != "" {
+		cfg.User, cfg.Pass = p.User, p.Pass
+		cfg.PassCommand = p.PassCommand
+	}
+	cfg.ProfileName = name
+	return nil
+}
+
+func (p *Profile) Endpoint() (string, error) {
+	host, port := p.Host, p.Port
+	if host == "" {
+		host = DEFAULT_HOST
+	}
+	if port == "" {
+		port = DEFAULT_PORT
+	}
+	return MakeURL(p.Url, host, port)
+}
+
+func (p *Profile) NewClient() (*rpc.Client, error) {
+	endpoint, err := p.Endpoint()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := rpc.NewClient(endpoint)
+	c.Username, c.Password, err = p.Credentials(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	err = c.SetTLS(&rpc.TLSOptions{
+		CAFile:             p.CACert,
+		CertFile:           p.Cert,
+		KeyFile:            p.Key,
+		InsecureSkipVerify: p.Insecure,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Sorted names of the profiles.
+func (cfg *Config) ProfileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for k := range cfg.Profiles {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Path of a file in the trango config dir.
+func ConfigPath(name string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return configDir + "/trango/" + name, nil
+}
+
+// Read the config file. A missing default file is not an error.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = ConfigPath(CONFIG_FILE); err != nil {
+			return cfg, nil
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return cfg, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Values of the flags given on the command line.
+var CmdFlags map[string]string
+
+// Remember the flags given on the command line, before ApplyFlags sets the
+// others.
+func SaveCmdFlags() {
+	CmdFlags = make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		CmdFlags[f.Name] = f.Value.String()
+	})
+}
+
+// Connection settings of the named profile, or of the top level ones if
+// name is empty. TRANGO_USER and TRANGO_PASS override the file, and the
+// command line flags override both.
+func (cfg Config) Connection(name string) (*Profile, error) {
+	if name != "" {
+		if err := cfg.SelectProfile(name); err != nil {
+			return nil, err
+		}
+	}
+	p := cfg.Profile
+	if u := os.Getenv("TRANGO_USER"); u != "" {
+		p.User = u
+	}
+	if pass := os.Getenv("TRANGO_PASS"); pass != "" {
+		p.Pass = pass
+	}
+	// Like -url, -host and -port replace the whole endpoint of the file.
+	_, host := CmdFlags["host"]
+	_, port := CmdFlags["port"]
+	if host || port {
+		p.Url = ""
+	}
+	for name, v := range CmdFlags {
+		switch name {
+		case "url":
+			p.Url = v
+		case "host":
+			p.Host = v
+		case "port":
+			p.Port = v
+		case "user":
+			p.User = v
+		case "pass":
+			p.Pass = v
+		case "cacert":
+			p.CACert = v
+		case "cert":
+			p.Cert = v
+		case "key":
+			p.Key = v
+		case "insecure":
+			p.Insecure = v == "true"
+		}
+	}
+	return &p, nil
+}
+
+// Set the flags which were not given on the command line from the config.
+func (cfg *Config) ApplyFlags() error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for name, v := range values {
+		if set[name] || flag.Lookup(name) == nil {
+			continue
+		}
+		if err := flag.Set(name, fmt.Sprint(v)); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Fill in the missing username/password from the pass_command output
+// or from the netrc entry of the host and the username.
+func (p *Profile) Credentials(host string) (string, string, error) {
+	user, pass := p.User, p.Pass
+	if pass == "" && p.PassCommand != "" {
+		cmd := exec.Command("sh", "-c", p.PassCommand)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", "", errors.New("pass_command: " + err.Error())
+		}
+		pass = strings.TrimRight(string(out), "\r\n")
+	}
+	if user == "" || pass == "" {
+		login, password, err := NetrcLookup(host, user)
+		if err != nil {
+			return "", "", err
+		}
+		if user == "" {
+			user = login
+		}
+		if pass == "" {
+			pass = password
+		}
+	}
+	return user, pass, nil
+}
+
+// Look up the login and password of the machine in $NETRC or ~/.netrc,
+// only the entries of login if it is not empty. A "default" entry is used
+// when there is no entry for the machine.
+func NetrcLookup(host, login string) (string, string, error) {
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", nil
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	return parseNetrc(f, host, login)
+}
+
+func parseNetrc(r io.Reader, host, login string) (string, string, error) {
+	type entry struct {
+		machine, login, password string
+		def                      bool
+	}
+	var entries []*entry
+	var cur *entry
+	sc := bufio.NewScanner(r)
+	var macdef bool
+	for sc.Scan() {
+		line := sc.Text()
+		if macdef {
+			// Macro definitions end with an empty line.
+			macdef = strings.TrimSpace(line) != ""
+			continue
+		}
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			next := func() string {
+				if i+1 < len(fields) {
+					i++
+					return fields[i]
+				}
+				return ""
+			}
+			switch fields[i] {
+			case "machine":
+				cur = &entry{machine: next()}
+				entries = append(entries, cur)
+			case "default":
+				cur = &entry{def: true}
+				entries = append(entries, cur)
+			case "login":
+				if cur != nil {
+					cur.login = next()
+				}
+			case "password":
+				if cur != nil {
+					cur.password = next()
+				}
+			case "account":
+				next()
+			case "macdef":
+				macdef = true
+				i = len(fields)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", "", err
+	}
+	var found, def *entry
+	for _, e := range entries {
+		if login != "" && e.login != login {
+			continue
+		}
+		if e.def && def == nil {
+			def = e
+		} else if !e.def && e.machine == host && found == nil {
+			found = e
+		}
+	}
+	if found == nil {
+		found = def
+	}
+	if found == nil {
+		return "", "", nil
+	}
+	return found.login, found.password, nil
+}
@@ -5,7 +5,6 @@ go 1.13
 require (
 	github.com/famz/SetLocale v0.0.0-20140414113655-0457ad1065dd
 	github.com/gdamore/tcell/v2 v2.2.0
-	github.com/marksamman/bencode v0.0.0-20150821143521-dc84f26e086e
 	github.com/rivo/tview v0.0.0-20210217110421-8a8f78a6dd01
 	golang.org/x/sys v0.0.0-20210227040730-b0d1d43c014d
 	golang.org/x/text v0.3.5
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/takiz/trango/metainfo"
+)
+
+// Output of -info with -json.
+type InfoJSON struct {
+	Name         string     `json:"name"`
+	InfoHash     string     `json:"info_hash"`
+	Size         int64      `json:"size"`
+	PieceLength  int64      `json:"piece_length"`
+	Pieces       int        `json:"pieces"`
+	Private      bool       `json:"private"`
+	Source       string     `json:"source,omitempty"`
+	Comment      string     `json:"comment,omitempty"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreationDate int64      `json:"creation_date,omitempty"` // Unix time.
+	Trackers     [][]string `json:"trackers"`
+	WebSeeds     []string   `json:"web_seeds"`
+	Files        []FileJSON `json:"files"`
+}
+
+type FileJSON struct {
+	Path   string `json:"path"` // Starts with the torrent name.
+	Length int64  `json:"length"`
+}
+
+// Print the contents of a torrent file without the daemon.
+func PrintTorrentInfo(filename string, asJSON bool) error {
+	m, err := metainfo.ParseFile(filename)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		return enc.Encode(NewInfoJSON(m))
+	}
+	yesNo := P("no")
+	if m.Private {
+		yesNo = P("yes")
+	}
+	rows := [][2]string{
+		{P("Name"), m.Name},
+		{P("Info hash"), m.HashString()},
+		{P("Size"), InfoSize(m.Length)},
+		{P("Piece size"), InfoSize(m.PieceLength)},
+		{P("Pieces"), fmt.Sprint(m.Pieces)},
+		{P("Private"), yesNo},
+		{P("Source"), m.Source},
+		{P("Comment"), m.Comment},
+		{P("Created by"), m.CreatedBy},
+	}
+	if !m.CreationDate.IsZero() {
+		rows = append(rows, [2]string{P("Created on"),
+			m.CreationDate.Format("2006-01-02 15:04:05")})
+	}
+	n := 0
+	for _, r := range rows {
+		if l := utf8.RuneCountInString(r[0]); l > n {
+			n = l
+		}
+	}
+	for _, r := range rows {
+		if r[1] != "" {
+			pad := strings.Repeat(" ", n-utf8.RuneCountInString(r[0]))
+			fmt.Printf("%s:%s %s\n", r[0], pad, r[1])
+		}
+	}
+	if tiers := m.Trackers(); len(tiers) > 0 {
+		fmt.Printf("\n%s:\n", P("Trackers"))
+		for i, tier := range tiers {
+			for j, t := range tier {
+				tierNum := ""
+				if j == 0 {
+					tierNum = fmt.Sprintf("[%d]", i+1)
+				}
+				fmt.Printf("  %-5s %s\n", tierNum, t)
+			}
+		}
+	}
+	if len(m.WebSeeds) > 0 {
+		fmt.Printf("\n%s:\n", P("Web seeds"))
+		for _, s := range m.WebSeeds {
+			fmt.Printf("  %s\n", s)
+		}
+	}
+	fmt.Printf("\n%s:\n", P("Files"))
+	if len(m.Files) == 0 {
+		fmt.Printf("  %s (%s)\n", m.Name, InfoSize(m.Length))
+		return nil
+	}
+	fmt.Printf("  %s/ (%s)\n", m.Name, InfoSize(m.Length))
+	PrintFileTree(m.Files, nil, "    ")
+	return nil
+}
+
+// Print the files under path with the sizes, the dirs first.
+func PrintFileTree(files []metainfo.File, path []string, indent string) {
+	names, _ := ReadFiles(files, path, len(path) == 0)
+	var dirs, other []string
+	for name, f := range names {
+		if f.Dir {
+			dirs = append(dirs, name)
+		} else {
+			other = append(other, name)
+		}
+	}
+	sort.Strings(dirs)
+	sort.Strings(other)
+	for _, name := range dirs {
+		sub := append(append([]string{}, path...), name)
+		_, length := ReadFiles(files, sub, false)
+		fmt.Printf("%s%s/ (%s)\n", indent, name, InfoSize(length))
+		PrintFileTree(files, sub, indent+"  ")
+	}
+	for _, name := range other {
+		fmt.Printf("%s%s (%s)\n", indent, name,
+			InfoSize(names[name].Length))
+	}
+}
+
+// Size without the padding of the torrent list.
+func InfoSize(bytes int64) string {
+	return strings.TrimSpace(FormatSize(bytes))
+}
+
+func NewInfoJSON(m *metainfo.MetaInfo) *InfoJSON {
+	info := &InfoJSON{
+		Name:        m.Name,
+		InfoHash:    m.HashString(),
+		Size:        m.Length,
+		PieceLength: m.PieceLength,
+		Pieces:      m.Pieces,
+		Private:     m.Private,
+		Source:      m.Source,
+		Comment:     m.Comment,
+		CreatedBy:   m.CreatedBy,
+		Trackers:    m.Trackers(),
+		WebSeeds:    m.WebSeeds,
+		Files:       make([]FileJSON, 0, m.FileCount()),
+	}
+	if !m.CreationDate.IsZero() {
+		info.CreationDate = m.CreationDate.Unix()
+	}
+	if info.Trackers == nil {
+		info.Trackers = [][]string{}
+	}
+	if info.WebSeeds == nil {
+		info.WebSeeds = []string{}
+	}
+	if len(m.Files) == 0 {
+		info.Files = append(info.Files, FileJSON{m.Name, m.Length})
+	}
+	for _, f := range m.Files {
+		path := m.Name + "/" + strings.Join(f.Path, "/")
+		info.Files = append(info.Files, FileJSON{path, f.Length})
+	}
+	return info
+}
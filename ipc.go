@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	SOCKET_NAME    = "trango.sock"
+	SOCKET_TIMEOUT = 5 * time.Second
+	REPLY_OK       = "ok"
+)
+
+// Torrents sent by other trango processes, shown in turn when the torrent
+// list is in front.
+var PendingAdds []*AddRequest
+
+// The socket of the running interface, empty without $XDG_RUNTIME_DIR.
+func SocketPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, SOCKET_NAME)
+}
+
+// Send the torrent to the running interface to show its add dialog.
+// Report false if no interface took it.
+func SendAdd(req *AddRequest) bool {
+	path := SocketPath()
+	if path == "" {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, SOCKET_TIMEOUT)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(SOCKET_TIMEOUT))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return false
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	return err == nil && strings.TrimSpace(reply) == REPLY_OK
+}
+
+// Take the -add requests of other trango processes. Only one interface
+// listens, the socket of an instance which has exited is replaced.
+func ListenAdd() (net.Listener, error) {
+	path := SocketPath()
+	if path == "" {
+		return nil, nil
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, nil
+	}
+	os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	go ServeAdd(l)
+	return l, nil
+}
+
+func ServeAdd(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(SOCKET_TIMEOUT))
+			req := &AddRequest{}
+			err := json.NewDecoder(conn).Decode(req)
+			if err != nil || req.Source == "" {
+				return
+			}
+			App.QueueUpdateDraw(func() {
+				PendingAdds = append(PendingAdds, req)
+				ShowPendingAdd()
+			})
+			conn.Write([]byte(REPLY_OK + "\n"))
+		}()
+	}
+}
+
+// Show the add dialog of the next pending torrent if no other view is open.
+func ShowPendingAdd() {
+	if len(PendingAdds) == 0 || ViewOpen || App.GetFocus() != MainList {
+		return
+	}
+	req := PendingAdds[0]
+	PendingAdds = PendingAdds[1:]
+	ShowAddBrowser(req)
+}
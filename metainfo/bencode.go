@@ -0,0 +1,149 @@
+package metainfo
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Nesting limit of lists and dictionaries.
+const maxDepth = 64
+
+// SyntaxError is returned for data which is not valid bencode.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("bencode: %s at offset %d", e.Msg, e.Offset)
+}
+
+// A decoded value is an int64, a string, a []interface{} or a dict.
+type dict map[string]interface{}
+
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+	// Raw bytes of the value of the top level "info" key.
+	info []byte
+}
+
+func (d *decoder) errorf(format string, a ...interface{}) error {
+	return &SyntaxError{Offset: d.pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (d *decoder) value() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, d.errorf("unexpected end of data")
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.integer()
+	case c >= '0' && c <= '9':
+		return d.str()
+	case c == 'l':
+		return d.list()
+	case c == 'd':
+		return d.dict()
+	default:
+		return nil, d.errorf("invalid character %q", c)
+	}
+}
+
+func (d *decoder) integer() (int64, error) {
+	start := d.pos + 1
+	end := start
+	for end < len(d.data) && d.data[end] != 'e' {
+		end++
+	}
+	if end == len(d.data) {
+		return 0, d.errorf("unterminated integer")
+	}
+	s := string(d.data[start:end])
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || s[0] == '+' || s == "-0" || len(s) > 1 && (s[0] == '0' ||
+		s[0] == '-' && s[1] == '0') {
+		return 0, d.errorf("invalid integer %q", s)
+	}
+	d.pos = end + 1
+	return n, nil
+}
+
+func (d *decoder) str() (string, error) {
+	colon := d.pos
+	for colon < len(d.data) && d.data[colon] != ':' {
+		colon++
+	}
+	if colon == len(d.data) {
+		return "", d.errorf("unterminated string length")
+	}
+	s := string(d.data[d.pos:colon])
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || len(s) > 1 && s[0] == '0' {
+		return "", d.errorf("invalid string length")
+	}
+	if n > len(d.data)-colon-1 {
+		return "", d.errorf("string longer than the data")
+	}
+	d.pos = colon + 1 + n
+	return string(d.data[colon+1 : d.pos]), nil
+}
+
+func (d *decoder) list() ([]interface{}, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, d.errorf("nesting too deep")
+	}
+	d.pos++
+	l := make([]interface{}, 0)
+	for {
+		if d.pos >= len(d.data) {
+			return nil, d.errorf("unterminated list")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			d.depth--
+			return l, nil
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+	}
+}
+
+func (d *decoder) dict() (dict, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, d.errorf("nesting too deep")
+	}
+	top := d.depth == 1
+	d.pos++
+	m := make(dict)
+	for {
+		if d.pos >= len(d.data) {
+			return nil, d.errorf("unterminated dictionary")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			d.depth--
+			return m, nil
+		}
+		if c := d.data[d.pos]; c < '0' || c > '9' {
+			return nil, d.errorf("dictionary key is not a string")
+		}
+		k, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		start := d.pos
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		if top && k == "info" {
+			d.info = d.data[start:d.pos]
+		}
+		m[k] = v
+	}
+}
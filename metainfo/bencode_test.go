@@ -0,0 +1,78 @@
+package metainfo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want interface{}
+	}{
+		{"i0e", int64(0)},
+		{"i-42e", int64(-42)},
+		{"i9223372036854775807e", int64(9223372036854775807)},
+		{"0:", ""},
+		{"4:spam", "spam"},
+		{"3:a:e", "a:e"},
+		{"le", []interface{}{}},
+		{"l4:spami7ee", []interface{}{"spam", int64(7)}},
+		{"de", dict{}},
+		{"d1:ai1e1:bl1:xee", dict{"a": int64(1), "b": []interface{}{"x"}}},
+	} {
+		d := &decoder{data: []byte(tt.in)}
+		got, err := d.value()
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %#v, want %#v", tt.in, got, tt.want)
+		}
+		if d.pos != len(tt.in) {
+			t.Errorf("%q: stopped at %d", tt.in, d.pos)
+		}
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	for _, tt := range []struct {
+		name, in string
+	}{
+		{"empty", ""},
+		{"invalid character", "x"},
+		{"unterminated integer", "i42"},
+		{"empty integer", "ie"},
+		{"integer leading zero", "i03e"},
+		{"negative zero", "i-0e"},
+		{"negative leading zero", "i-03e"},
+		{"integer plus sign", "i+3e"},
+		{"integer overflow", "i9223372036854775808e"},
+		{"integer not a number", "i1.5e"},
+		{"truncated string", "5:abc"},
+		{"string without colon", "5abc"},
+		{"string without data", "1:"},
+		{"string length leading zero", "03:abc"},
+		{"string length not a number", "1a:abc"},
+		{"huge string length", "9999999999:abc"},
+		{"string length overflow", "99999999999999999999:abc"},
+		{"unterminated list", "l4:spam"},
+		{"unterminated nested list", "lli1ee"},
+		{"unterminated dictionary", "d1:ai1e"},
+		{"dictionary without value", "d1:ae"},
+		{"integer key", "di1ei2ee"},
+		{"list key", "dle1:ae"},
+		{"dictionary key", "dde1:ae"},
+		{"too deep", strings.Repeat("l", maxDepth+1) +
+			strings.Repeat("e", maxDepth+1)},
+	} {
+		d := &decoder{data: []byte(tt.in)}
+		if _, err := d.value(); err == nil {
+			t.Errorf("%s: %q: no error", tt.name, tt.in)
+		} else if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("%s: %q: %T is not a *SyntaxError", tt.name, tt.in, err)
+		}
+	}
+}
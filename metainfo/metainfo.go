@@ -0,0 +1,329 @@
+// Package metainfo parses BitTorrent metainfo (.torrent) files.
+package metainfo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Larger files are not torrents.
+const MaxSize = 64 << 20
+
+// Error is returned for a metainfo field which is missing or invalid.
+type Error struct {
+	Field string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return "metainfo: " + e.Field + ": " + e.Msg
+}
+
+type File struct {
+	Path   []string
+	Length int64
+}
+
+type MetaInfo struct {
+	Name        string
+	InfoHash    [sha1.Size]byte
+	PieceLength int64
+	Pieces      int
+	Private     bool
+	Source      string
+	// Files of a multi file torrent, empty for a single file one.
+	Files  []File
+	Length int64 // Total size.
+
+	Comment      string
+	CreatedBy    string
+	CreationDate time.Time // Zero if unknown.
+	Announce     string
+	AnnounceList [][]string
+	WebSeeds     []string // url-list
+}
+
+// HashString returns the info-hash in hex.
+func (m *MetaInfo) HashString() string {
+	return hex.EncodeToString(m.InfoHash[:])
+}
+
+// Trackers returns the tracker tiers, the announce-list if there is one
+// and the announce URL otherwise.
+func (m *MetaInfo) Trackers() [][]string {
+	if len(m.AnnounceList) > 0 {
+		return m.AnnounceList
+	}
+	if m.Announce != "" {
+		return [][]string{{m.Announce}}
+	}
+	return nil
+}
+
+// FileCount returns the number of files, 1 for a single file torrent.
+func (m *MetaInfo) FileCount() int {
+	if len(m.Files) == 0 {
+		return 1
+	}
+	return len(m.Files)
+}
+
+// ParseFile reads the metainfo file, the errors start with its name.
+func ParseFile(filename string) (*MetaInfo, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return m, nil
+}
+
+// Parse decodes the metainfo and computes its info-hash from the raw
+// "info" dictionary.
+func Parse(r io.Reader) (*MetaInfo, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxSize {
+		return nil, errors.New("metainfo: file too large")
+	}
+	if len(data) == 0 || data[0] != 'd' {
+		return nil, &SyntaxError{Offset: 0, Msg: "not a dictionary"}
+	}
+	d := &decoder{data: data}
+	top, err := d.dict()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, d.errorf("trailing data after the dictionary")
+	}
+	info, ok := top["info"].(dict)
+	if !ok {
+		return nil, &Error{Field: "info", Msg: "missing or not a dictionary"}
+	}
+	m := &MetaInfo{InfoHash: sha1.Sum(d.info)}
+	if err := m.parseInfo(info); err != nil {
+		return nil, err
+	}
+	f := fields{top, ""}
+	if m.Announce, err = f.str("announce"); err != nil {
+		return nil, err
+	}
+	if m.AnnounceList, err = f.tiers("announce-list"); err != nil {
+		return nil, err
+	}
+	if m.Comment, err = f.utf8("comment"); err != nil {
+		return nil, err
+	}
+	if m.CreatedBy, err = f.utf8("created by"); err != nil {
+		return nil, err
+	}
+	date, ok, err := f.integer("creation date")
+	if err != nil {
+		return nil, err
+	} else if ok && date > 0 {
+		m.CreationDate = time.Unix(date, 0)
+	}
+	if m.WebSeeds, err = f.strs("url-list"); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *MetaInfo) parseInfo(info dict) error {
+	f := fields{info, "info."}
+	var err error
+	if m.Name, err = f.utf8("name"); err != nil {
+		return err
+	} else if m.Name == "" {
+		return f.errorf("name", "missing")
+	}
+	n, ok, err := f.integer("piece length")
+	if err != nil {
+		return err
+	} else if !ok || n <= 0 {
+		return f.errorf("piece length", "missing or not positive")
+	}
+	m.PieceLength = n
+	pieces, err := f.str("pieces")
+	if err != nil {
+		return err
+	} else if pieces == "" {
+		return f.errorf("pieces", "missing")
+	} else if len(pieces)%sha1.Size != 0 {
+		return f.errorf("pieces", "length is not a multiple of 20")
+	}
+	m.Pieces = len(pieces) / sha1.Size
+	private, _, err := f.integer("private")
+	if err != nil {
+		return err
+	}
+	m.Private = private == 1
+	if m.Source, err = f.str("source"); err != nil {
+		return err
+	}
+	length, single, err := f.integer("length")
+	if err != nil {
+		return err
+	}
+	files, multi := info["files"]
+	switch {
+	case single && multi:
+		return f.errorf("files", "both length and files are given")
+	case single:
+		if length < 0 {
+			return f.errorf("length", "negative")
+		}
+		m.Length = length
+	case multi:
+		return m.parseFiles(files)
+	default:
+		return f.errorf("files", "neither length nor files is given")
+	}
+	return nil
+}
+
+func (m *MetaInfo) parseFiles(v interface{}) error {
+	files, ok := v.([]interface{})
+	if !ok || len(files) == 0 {
+		return &Error{Field: "info.files", Msg: "not a list of files"}
+	}
+	m.Files = make([]File, len(files))
+	for i, fv := range files {
+		d, ok := fv.(dict)
+		if !ok {
+			return &Error{Field: fmt.Sprintf("info.files[%d]", i),
+				Msg: "not a dictionary"}
+		}
+		f := fields{d, fmt.Sprintf("info.files[%d].", i)}
+		length, ok, err := f.integer("length")
+		if err != nil {
+			return err
+		} else if !ok || length < 0 {
+			return f.errorf("length", "missing or negative")
+		}
+		key := "path.utf-8"
+		if _, ok := d[key]; !ok {
+			key = "path"
+		}
+		path, err := f.strs(key)
+		if err != nil {
+			return err
+		} else if len(path) == 0 {
+			return f.errorf("path", "missing")
+		}
+		m.Files[i] = File{Path: path, Length: length}
+		m.Length += length
+	}
+	return nil
+}
+
+// Typed access to the values of a dictionary, prefix is the path of the
+// dictionary for the errors.
+type fields struct {
+	d      dict
+	prefix string
+}
+
+func (f fields) errorf(key, msg string) error {
+	return &Error{Field: f.prefix + key, Msg: msg}
+}
+
+func (f fields) str(key string) (string, error) {
+	v, ok := f.d[key]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", f.errorf(key, "not a string")
+	}
+	return s, nil
+}
+
+// The UTF-8 variant of the key is preferred.
+func (f fields) utf8(key string) (string, error) {
+	if _, ok := f.d[key+".utf-8"]; ok {
+		return f.str(key + ".utf-8")
+	}
+	return f.str(key)
+}
+
+func (f fields) integer(key string) (int64, bool, error) {
+	v, ok := f.d[key]
+	if !ok {
+		return 0, false, nil
+	}
+	n, ok := v.(int64)
+	if !ok {
+		return 0, false, f.errorf(key, "not an integer")
+	}
+	return n, true, nil
+}
+
+// A list of strings, a single string is a list of one.
+func (f fields) strs(key string) ([]string, error) {
+	v, ok := f.d[key]
+	if !ok {
+		return nil, nil
+	}
+	if s, ok := v.(string); ok {
+		return []string{s}, nil
+	}
+	l, ok := v.([]interface{})
+	if !ok {
+		return nil, f.errorf(key, "not a list")
+	}
+	res := make([]string, len(l))
+	for i, e := range l {
+		if res[i], ok = e.(string); !ok {
+			return nil, f.errorf(key, "not a list of strings")
+		}
+	}
+	return res, nil
+}
+
+// Tiers of tracker URLs without the empty ones.
+func (f fields) tiers(key string) ([][]string, error) {
+	v, ok := f.d[key]
+	if !ok {
+		return nil, nil
+	}
+	l, ok := v.([]interface{})
+	if !ok {
+		return nil, f.errorf(key, "not a list")
+	}
+	var res [][]string
+	for _, e := range l {
+		t, ok := e.([]interface{})
+		if !ok {
+			return nil, f.errorf(key, "not a list of lists")
+		}
+		tier := make([]string, 0, len(t))
+		for _, u := range t {
+			s, ok := u.(string)
+			if !ok {
+				return nil, f.errorf(key, "not a list of URLs")
+			}
+			if s != "" {
+				tier = append(tier, s)
+			}
+		}
+		if len(tier) > 0 {
+			res = append(res, tier)
+		}
+	}
+	return res, nil
+}
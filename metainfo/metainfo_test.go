@@ -0,0 +1,131 @@
+package metainfo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const pieces = "6:pieces20:abcdefghijklmnopqrst"
+
+// Torrents and the sha1sum of their info dictionaries.
+var (
+	singleInfo    = "d6:lengthi1000e4:name5:a.txt12:piece lengthi16384e" + pieces + "e"
+	singleTorrent = "d8:announce20:http://t.example/ann" +
+		"13:creation datei1600000000e4:info" + singleInfo + "e"
+	singleHash = "53eee3a983e1894656f75f21a6762aed95c1dd55"
+
+	multiTorrent = "d13:announce-listll0:e" +
+		"l14:http://t1.test14:http://t2.testee" +
+		"4:infod5:filesld6:lengthi3e4:pathl1:a5:x.txteed6:lengthi5e" +
+		"4:pathl5:b.bineee4:name4:demo12:piece lengthi16384e" + pieces +
+		"7:privatei1ee8:url-list15:http://ws.test/e"
+	multiHash = "44ddf4335fbc2632f4c3925a1449c79df41adbdb"
+)
+
+func TestParseSingle(t *testing.T) {
+	m, err := Parse(strings.NewReader(singleTorrent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := m.HashString(); h != singleHash {
+		t.Errorf("info-hash %s, want %s", h, singleHash)
+	}
+	if m.Name != "a.txt" || m.Length != 1000 || m.PieceLength != 16384 ||
+		m.Pieces != 1 || m.Private || m.FileCount() != 1 {
+		t.Errorf("got %+v", m)
+	}
+	if m.CreationDate.Unix() != 1600000000 {
+		t.Errorf("creation date %v", m.CreationDate)
+	}
+	want := [][]string{{"http://t.example/ann"}}
+	if got := m.Trackers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("trackers %q, want %q", got, want)
+	}
+}
+
+func TestParseMulti(t *testing.T) {
+	m, err := Parse(strings.NewReader(multiTorrent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := m.HashString(); h != multiHash {
+		t.Errorf("info-hash %s, want %s", h, multiHash)
+	}
+	files := []File{{[]string{"a", "x.txt"}, 3}, {[]string{"b.bin"}, 5}}
+	if !reflect.DeepEqual(m.Files, files) || m.Length != 8 || !m.Private {
+		t.Errorf("got %+v", m)
+	}
+	// The empty tracker URLs are dropped.
+	tiers := [][]string{{"http://t1.test", "http://t2.test"}}
+	if got := m.Trackers(); !reflect.DeepEqual(got, tiers) {
+		t.Errorf("trackers %q, want %q", got, tiers)
+	}
+	if !reflect.DeepEqual(m.WebSeeds, []string{"http://ws.test/"}) {
+		t.Errorf("web seeds %q", m.WebSeeds)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	info := func(s string) string { return "d4:info" + s + "e" }
+	for _, tt := range []struct {
+		name, in string
+		field    string // Empty for a *SyntaxError.
+	}{
+		{"empty", "", ""},
+		{"not a dictionary", "l4:infoe", ""},
+		{"trailing data", singleTorrent + "x", ""},
+		{"second dictionary", singleTorrent + "de", ""},
+		{"truncated", singleTorrent[:len(singleTorrent)-1], ""},
+		{"no info", "d8:announce1:xe", "info"},
+		{"info not a dictionary", info("i1e"), "info"},
+		{"no name", info("d6:lengthi1e12:piece lengthi1e" + pieces + "e"),
+			"info.name"},
+		{"no piece length", info("d6:lengthi1e4:name1:a" + pieces + "e"),
+			"info.piece length"},
+		{"short pieces", info("d6:lengthi1e4:name1:a12:piece lengthi1e" +
+			"6:pieces3:abce"), "info.pieces"},
+		{"no length", info("d4:name1:a12:piece lengthi1e" + pieces + "e"),
+			"info.files"},
+		{"negative file length", info("d5:filesld6:lengthi-1e4:pathl1:aeee" +
+			"4:name1:a12:piece lengthi1e" + pieces + "e"),
+			"info.files[0].length"},
+		{"announce not a string", "d8:announcei1e4:info" + singleInfo + "e",
+			"announce"},
+	} {
+		_, err := Parse(strings.NewReader(tt.in))
+		switch e := err.(type) {
+		case nil:
+			t.Errorf("%s: no error", tt.name)
+		case *SyntaxError:
+			if tt.field != "" {
+				t.Errorf("%s: %v, want an error of %s", tt.name, err, tt.field)
+			}
+		case *Error:
+			if e.Field != tt.field {
+				t.Errorf("%s: %v, want an error of %q", tt.name, err, tt.field)
+			}
+		default:
+			t.Errorf("%s: unexpected %T: %v", tt.name, err, err)
+		}
+	}
+}
+
+// Every truncation and many byte changes of the valid torrents are parsed
+// without a panic, the truncated ones with an error.
+func TestParseCorpus(t *testing.T) {
+	subst := []byte("eild0129:-x\x00\xff")
+	for _, s := range []string{singleTorrent, multiTorrent} {
+		for i := 0; i < len(s); i++ {
+			if _, err := Parse(strings.NewReader(s[:i])); err == nil {
+				t.Errorf("%q: no error", s[:i])
+			}
+			b := []byte(s)
+			for _, c := range subst {
+				b[i] = c
+				Parse(strings.NewReader(string(b)))
+			}
+			Parse(strings.NewReader(s[:i] + s[i+1:]))
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package rpc
+
+type TorrentGetArgs struct {
+	Fields []string    `json:"fields"`
+	Ids    interface{} `json:"ids,omitempty"` // []int or RecentlyActive
+	Format string      `json:"format,omitempty"`
+}
+
+const RecentlyActive = "recently-active"
+
+type SessionGetArgs struct {
+	Fields []string `json:"fields,omitempty"`
+}
+
+type IdsArgs struct {
+	Ids []int `json:"ids"`
+}
+
+type TorrentRemoveArgs struct {
+	DeleteData bool  `json:"delete-local-data"`
+	Ids        []int `json:"ids"`
+}
+
+type TorrentSetLocationArgs struct {
+	Location string `json:"location"`
+	Move     bool   `json:"move"`
+	Ids      []int  `json:"ids"`
+}
+
+type TorrentRenamePathArgs struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	Ids  []int  `json:"ids"`
+}
+
+type TorrentAddArgs struct {
+	Filename          string   `json:"filename,omitempty"`
+	Metainfo          string   `json:"metainfo,omitempty"`
+	Paused            bool     `json:"paused"`
+	DownloadDir       string   `json:"download-dir,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
+	FilesWanted       []int    `json:"files-wanted,omitempty"`
+	FilesUnwanted     []int    `json:"files-unwanted,omitempty"`
+	PriorityHigh      []int    `json:"priority-high,omitempty"`
+	PriorityLow       []int    `json:"priority-low,omitempty"`
+	BandwidthPriority int      `json:"bandwidthPriority,omitempty"`
+	PeerLimit         int      `json:"peer-limit,omitempty"`
+}
+
+type FreeSpaceArgs struct {
+	Path string `json:"path"`
+}
+
+type FreeSpace struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size-bytes"`
+	Total int64  `json:"total_size,omitempty"` // Since Transmission 4.0
+}
+
+type AddedTorrent struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	HashString string `json:"hashString"`
+}
+
+type TorrentAddResult struct {
+	Added     *AddedTorrent `json:"torrent-added,omitempty"`
+	Duplicate *AddedTorrent `json:"torrent-duplicate,omitempty"`
+}
+
+// Torrent returns the added or the already existing torrent.
+func (r *TorrentAddResult) Torrent() *AddedTorrent {
+	if r.Duplicate != nil {
+		return r.Duplicate
+	}
+	return r.Added
+}
+
+// TorrentGet decodes the requested fields of the torrents into out, which
+// should have a "torrents" field. All torrents are requested if ids is empty.
+func (c *Client) TorrentGet(ids []int, fields []string, out interface{}) error {
+	args := &TorrentGetArgs{Fields: fields}
+	if len(ids) > 0 {
+		args.Ids = ids
+	}
+	return c.torrentGet(args, out)
+}
+
+// TorrentGetRecent is like TorrentGet for the torrents changed since the last
+// minute. The ids of the removed torrents are decoded into the "removed"
+// field of out.
+func (c *Client) TorrentGetRecent(fields []string, out interface{}) error {
+	return c.torrentGet(&TorrentGetArgs{Fields: fields,
+		Ids: RecentlyActive}, out)
+}
+
+// TorrentSet applies args (including its "ids") with torrent-set.
+func (c *Client) TorrentSet(args interface{}) error {
+	return c.Call("torrent-set", args, nil)
+}
+
+func (c *Client) TorrentAdd(args interface{}) (*TorrentAddResult, error) {
+	res := &TorrentAddResult{}
+	if err := c.Call("torrent-add", args, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// TorrentAction calls one of torrent-start, torrent-stop, torrent-verify,
+// torrent-reannounce, queue-move-up and similar methods which take only ids.
+func (c *Client) TorrentAction(method string, ids []int) error {
+	return c.Call(method, &IdsArgs{Ids: ids}, nil)
+}
+
+func (c *Client) TorrentRemove(ids []int, deleteData bool) error {
+	return c.Call("torrent-remove",
+		&TorrentRemoveArgs{DeleteData: deleteData, Ids: ids}, nil)
+}
+
+func (c *Client) TorrentSetLocation(ids []int, location string, move bool) error {
+	return c.Call("torrent-set-location", &TorrentSetLocationArgs{
+		Location: location, Move: move, Ids: ids}, nil)
+}
+
+func (c *Client) TorrentRenamePath(id int, path, name string) error {
+	return c.Call("torrent-rename-path", &TorrentRenamePathArgs{
+		Path: path, Name: name, Ids: []int{id}}, nil)
+}
+
+// SessionGet decodes the session arguments into out.
+func (c *Client) SessionGet(out interface{}) error {
+	return c.Call("session-get", nil, out)
+}
+
+func (c *Client) SessionSet(args interface{}) error {
+	return c.Call("session-set", args, nil)
+}
+
+// FreeSpace returns the free space of a path on the daemon side.
+func (c *Client) FreeSpace(path string) (*FreeSpace, error) {
+	res := &FreeSpace{}
+	if err := c.Call("free-space", &FreeSpaceArgs{Path: path}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) SessionStats(out interface{}) error {
+	return c.Call("session-stats", nil, out)
+}
@@ -0,0 +1,201 @@
+// Package rpc implements a client for the Transmission RPC protocol.
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const SessionIdHeader = "X-Transmission-Session-Id"
+
+type Request struct {
+	Method string      `json:"method"`
+	Args   interface{} `json:"arguments,omitempty"`
+}
+
+type Response struct {
+	Args   interface{} `json:"arguments"`
+	Result string      `json:"result"`
+}
+
+// TransportError is returned when the daemon can not be reached or the
+// connection breaks before a response is read.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return "rpc: " + e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPError is returned when the daemon answers with an unexpected HTTP status.
+type HTTPError struct {
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("rpc: %d: %s", e.StatusCode,
+		http.StatusText(e.StatusCode))
+}
+
+// ResultError is returned when the response "result" is not "success".
+type ResultError struct {
+	Method string
+	Result string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("rpc: %s: %s", e.Method, e.Result)
+}
+
+// IsConnError reports whether err means the daemon is unreachable,
+// e.g. it is restarting or the network is down.
+func IsConnError(err error) bool {
+	var te *TransportError
+	var he *HTTPError
+	return errors.As(err, &te) || errors.As(err, &he) &&
+		he.StatusCode >= http.StatusBadGateway
+}
+
+// Client settings may be changed directly only before the first call,
+// later use Assign.
+type Client struct {
+	URL        string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+
+	mu          sync.Mutex
+	sessionId   string
+	generation  int
+	tableFormat bool
+}
+
+func NewClient(url string) *Client {
+	return &Client{URL: url, HTTPClient: &http.Client{}}
+}
+
+// SessionId returns the last X-Transmission-Session-Id sent by the daemon.
+func (c *Client) SessionId() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionId
+}
+
+func (c *Client) setSessionId(id string) {
+	c.mu.Lock()
+	c.sessionId = id
+	c.mu.Unlock()
+}
+
+// ResetSession forgets the session id, so the next call renews it.
+func (c *Client) ResetSession() {
+	c.setSessionId("")
+}
+
+// Assign switches the client to the daemon and credentials of n.
+// Calls in flight finish with the previous settings.
+func (c *Client) Assign(n *Client) {
+	c.mu.Lock()
+	c.URL, c.HTTPClient = n.URL, n.HTTPClient
+	c.Username, c.Password = n.Username, n.Password
+	c.tableFormat = n.tableFormat
+	c.sessionId = ""
+	c.generation++
+	c.mu.Unlock()
+}
+
+// IsLocal reports whether the daemon runs on this machine, so its paths
+// can be checked locally.
+func (c *Client) IsLocal() bool {
+	c.mu.Lock()
+	rawurl := c.URL
+	c.mu.Unlock()
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Generation is incremented by Assign. Compare it before and after a call
+// to drop results of the previous daemon.
+func (c *Client) Generation() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Call sends the method with args and decodes the response arguments into out.
+// Args and out may be nil.
+func (c *Client) Call(method string, args, out interface{}) error {
+	pdata, err := json.Marshal(&Request{Method: method, Args: args})
+	if err != nil {
+		return err
+	}
+	data, err := c.post(pdata)
+	if err != nil {
+		return err
+	}
+	res := &Response{Args: out}
+	if err := json.Unmarshal(data, res); err != nil {
+		return err
+	}
+	if res.Result != "success" {
+		return &ResultError{Method: method, Result: res.Result}
+	}
+	return nil
+}
+
+// Post the request body, renewing the session id once on 409 Conflict.
+func (c *Client) post(pdata []byte) ([]byte, error) {
+	for i := 0; ; i++ {
+		c.mu.Lock()
+		url, hc := c.URL, c.HTTPClient
+		user, pass := c.Username, c.Password
+		sessionId := c.sessionId
+		c.mu.Unlock()
+		req, err := http.NewRequest("POST", url, bytes.NewReader(pdata))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(SessionIdHeader, sessionId)
+		if user != "" || pass != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		resp, err := hc.Do(req)
+		if err != nil {
+			return nil, &TransportError{err}
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, &TransportError{err}
+		}
+		switch {
+		case resp.StatusCode == http.StatusConflict && i == 0:
+			c.setSessionId(resp.Header.Get(SessionIdHeader))
+			continue
+		case resp.StatusCode != http.StatusOK:
+			return nil, &HTTPError{resp.StatusCode}
+		}
+		return data, nil
+	}
+}
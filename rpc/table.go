@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// UseTableFormat makes torrent-get request the "table" format, which sends
+// the field names once instead of in every torrent. It is supported since
+// rpc-version 16, see Features.
+func (c *Client) UseTableFormat(on bool) {
+	c.mu.Lock()
+	c.tableFormat = on
+	c.mu.Unlock()
+}
+
+func (c *Client) torrentGet(args *TorrentGetArgs, out interface{}) error {
+	c.mu.Lock()
+	table := c.tableFormat
+	c.mu.Unlock()
+	if !table {
+		return c.Call("torrent-get", args, out)
+	}
+	args.Format = "table"
+	var raw json.RawMessage
+	if err := c.Call("torrent-get", args, &raw); err != nil {
+		return err
+	}
+	data, err := tableToObjects(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// Convert the "torrents" table of the response arguments, where the first
+// row is the field names, to an array of objects.
+func tableToObjects(raw json.RawMessage) ([]byte, error) {
+	var args map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	var rows [][]json.RawMessage
+	if err := json.Unmarshal(args["torrents"], &rows); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 1; i < len(rows); i++ {
+		if len(rows[i]) != len(rows[0]) {
+			return nil, errors.New("rpc: torrent-get: malformed table")
+		}
+		if i > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, v := range rows[i] {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			// The names are JSON strings already.
+			buf.Write(rows[0][j])
+			buf.WriteByte(':')
+			buf.Write(v)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	args["torrents"] = buf.Bytes()
+	return json.Marshal(args)
+}
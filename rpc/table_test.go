@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type tableTorrent struct {
+	Id       int      `json:"id"`
+	Name     string   `json:"name"`
+	Labels   []string `json:"labels"`
+	Progress float64  `json:"percentDone"`
+	Eta      int64    `json:"eta"`
+}
+
+type tableArgs struct {
+	Torrents []tableTorrent `json:"torrents"`
+	Removed  []int          `json:"removed"`
+}
+
+// The response of a daemon in the object format.
+const objectFixture = `{
+	"torrents": [
+		{"id": 1, "name": "a \"quoted\" name", "labels": ["x", "y"], "percentDone": 0.5, "eta": -1},
+		{"id": 2, "name": "no labels", "percentDone": 1},
+		{"id": 3, "name": "ünïcode", "labels": [], "eta": 3600}
+	],
+	"removed": [7, 8]
+}`
+
+// The same response in the table format, the fields missing above are null.
+const tableFixture = `{
+	"torrents": [
+		["id", "name", "labels", "percentDone", "eta"],
+		[1, "a \"quoted\" name", ["x", "y"], 0.5, -1],
+		[2, "no labels", null, 1, null],
+		[3, "ünïcode", [], null, 3600]
+	],
+	"removed": [7, 8]
+}`
+
+func TestTableToObjects(t *testing.T) {
+	var want, got tableArgs
+	if err := json.Unmarshal([]byte(objectFixture), &want); err != nil {
+		t.Fatal(err)
+	}
+	data, err := tableToObjects(json.RawMessage(tableFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("%v: %s", err, data)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTableToObjectsHeaderOnly(t *testing.T) {
+	data, err := tableToObjects(json.RawMessage(
+		`{"torrents": [["id", "name"]], "removed": [1]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got tableArgs
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Torrents) != 0 || !reflect.DeepEqual(got.Removed, []int{1}) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestTableToObjectsMalformed(t *testing.T) {
+	for _, s := range []string{
+		`{"torrents": [["id", "name"], [1]]}`,
+		`{"torrents": [["id"], [1, 2]]}`,
+		`{"torrents": {"id": 1}}`,
+		`{"torrents": [["id"], [1]]`,
+	} {
+		if _, err := tableToObjects(json.RawMessage(s)); err == nil {
+			t.Errorf("%s: no error", s)
+		}
+	}
+}
+
+// A daemon of the rpc-version, an older one than 16 ignores the format.
+func newTableDaemon(t *testing.T, version int, formats *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Args   struct {
+				Format string `json:"format"`
+			} `json:"arguments"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		args := `{"rpc-version": ` + fmt.Sprint(version) + `}`
+		if req.Method == "torrent-get" {
+			*formats = append(*formats, req.Args.Format)
+			args = objectFixture
+			if req.Args.Format == "table" && version >= 16 {
+				args = tableFixture
+			}
+		}
+		fmt.Fprintf(w, `{"arguments": %s, "result": "success"}`, args)
+	}))
+}
+
+func TestTorrentGetFormat(t *testing.T) {
+	var want tableArgs
+	if err := json.Unmarshal([]byte(objectFixture), &want); err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"id", "name", "labels", "percentDone", "eta"}
+	for _, tt := range []struct {
+		version int
+		format  string // Sent with torrent-get.
+	}{
+		{15, ""},
+		{16, "table"},
+		{17, "table"},
+	} {
+		var formats []string
+		srv := newTableDaemon(t, tt.version, &formats)
+		c := NewClient(srv.URL)
+		v, err := c.SessionVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.UseTableFormat(v.Features().TableFormat)
+		var got tableArgs
+		if err := c.TorrentGet(nil, fields, &got); err != nil {
+			t.Errorf("version %d: %v", tt.version, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("version %d: got %+v, want %+v", tt.version, got, want)
+		}
+		// The recently active torrents use the same format.
+		got = tableArgs{}
+		if err := c.TorrentGetRecent(fields, &got); err != nil {
+			t.Errorf("version %d: %v", tt.version, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("version %d: recent: got %+v, want %+v", tt.version,
+				got, want)
+		}
+		if !reflect.DeepEqual(formats, []string{tt.format, tt.format}) {
+			t.Errorf("version %d: formats %q, want %q", tt.version, formats,
+				tt.format)
+		}
+		srv.Close()
+	}
+}
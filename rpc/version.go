@@ -0,0 +1,43 @@
+package rpc
+
+// Version of the daemon and its RPC, from session-get.
+type Version struct {
+	Version    string `json:"version"`
+	RPCVersion int    `json:"rpc-version"`
+	// Since rpc-version 17 (Transmission 4.0.0).
+	RPCSemver  string `json:"rpc-version-semver"`
+	RPCMinimum int    `json:"rpc-version-minimum"`
+}
+
+// Features which depend on the RPC version of the daemon.
+type Features struct {
+	Labels             bool // Torrent labels
+	AddLabels          bool // "labels" of torrent-add
+	TableFormat        bool // "format": "table" of torrent-get
+	TrackerList        bool // "trackerList" of torrent-get and torrent-set
+	BandwidthGroups    bool // group-get and group-set
+	SequentialDownload bool // "sequentialDownload" of torrent-get and torrent-set
+}
+
+// Features of the RPC version.
+func (v *Version) Features() Features {
+	return Features{
+		Labels:             v.RPCVersion >= 16, // 3.00
+		AddLabels:          v.RPCVersion >= 17,
+		TableFormat:        v.RPCVersion >= 16,
+		TrackerList:        v.RPCVersion >= 17, // 4.0.0
+		BandwidthGroups:    v.RPCVersion >= 17,
+		SequentialDownload: v.RPCVersion >= 18, // 4.1.0
+	}
+}
+
+// SessionVersion returns the version fields of session-get.
+func (c *Client) SessionVersion() (*Version, error) {
+	v := &Version{}
+	if err := c.Call("session-get", &SessionGetArgs{Fields: []string{
+		"version", "rpc-version", "rpc-version-semver",
+		"rpc-version-minimum"}}, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
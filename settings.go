@@ -0,0 +1,580 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/takiz/trango/rpc"
+)
+
+const (
+	SETTING_BOOL = iota
+	SETTING_INT
+	SETTING_FLOAT
+	SETTING_TEXT
+	SETTING_CHOICE
+	SETTING_TIME // Minutes since midnight shown as HH:MM
+	SETTING_DAYS // Day mask of the alt speed schedule
+	SETTING_DAY  // Checkbox of one day of a SETTING_DAYS mask
+	SETTING_MODE // Number picked from Choices, the first one is Min
+)
+
+// Masks of alt-speed-time-day, Sunday is the lowest bit.
+const (
+	DAYS_WEEKDAYS = 62
+	DAYS_WEEKEND  = 65
+	DAYS_ALL      = 127
+)
+
+// Alternative speed limits and their schedule.
+type AltSpeed struct {
+	Enabled     bool `json:"alt-speed-enabled"`
+	Down        int  `json:"alt-speed-down"` // kB/s
+	Up          int  `json:"alt-speed-up"`
+	TimeEnabled bool `json:"alt-speed-time-enabled"`
+	Begin       int  `json:"alt-speed-time-begin"` // Minutes since midnight
+	End         int  `json:"alt-speed-time-end"`
+	Day         int  `json:"alt-speed-time-day"`
+}
+
+var AltSpeedFields = []string{"alt-speed-enabled", "alt-speed-down",
+	"alt-speed-up", "alt-speed-time-enabled", "alt-speed-time-begin",
+	"alt-speed-time-end", "alt-speed-time-day"}
+
+// A session-get/session-set argument.
+type Setting struct {
+	Key     string
+	Label   string
+	Type    int
+	Min     float64 // Range of the numbers, no upper bound if Max is 0
+	Max     float64
+	Choices []string
+	Day     int // Bit of a SETTING_DAY in the mask
+}
+
+type SettingsPage struct {
+	Name     string
+	Settings []Setting
+}
+
+func SettingsPages() []SettingsPage {
+	return []SettingsPage{
+		{P("Speed"), []Setting{
+			{Key: "speed-limit-down-enabled", Label: P("Limit download speed"), Type: SETTING_BOOL},
+			{Key: "speed-limit-down", Label: P("Download limit (kB/s)"), Type: SETTING_INT},
+			{Key: "speed-limit-up-enabled", Label: P("Limit upload speed"), Type: SETTING_BOOL},
+			{Key: "speed-limit-up", Label: P("Upload limit (kB/s)"), Type: SETTING_INT},
+		}},
+		{P("Peers"), []Setting{
+			{Key: "peer-limit-global", Label: P("Global peer limit"), Type: SETTING_INT, Min: 1},
+			{Key: "peer-limit-per-torrent", Label: P("Peer limit per torrent"), Type: SETTING_INT, Min: 1},
+			{Key: "encryption", Label: P("Encryption"), Type: SETTING_CHOICE,
+				Choices: []string{"required", "preferred", "tolerated"}},
+			{Key: "dht-enabled", Label: P("DHT"), Type: SETTING_BOOL},
+			{Key: "pex-enabled", Label: P("PEX"), Type: SETTING_BOOL},
+			{Key: "lpd-enabled", Label: P("Local peer discovery"), Type: SETTING_BOOL},
+			{Key: "utp-enabled", Label: P("uTP"), Type: SETTING_BOOL},
+		}},
+		{P("Network"), []Setting{
+			{Key: "peer-port", Label: P("Peer port"), Type: SETTING_INT, Min: 1, Max: 65535},
+			{Key: "peer-port-random-on-start", Label: P("Random port on start"), Type: SETTING_BOOL},
+			{Key: "port-forwarding-enabled", Label: P("Port forwarding"), Type: SETTING_BOOL},
+			{Key: "blocklist-enabled", Label: P("Blocklist"), Type: SETTING_BOOL},
+			{Key: "blocklist-url", Label: P("Blocklist URL"), Type: SETTING_TEXT},
+		}},
+		{P("Queue"), []Setting{
+			{Key: "download-queue-enabled", Label: P("Download queue"), Type: SETTING_BOOL},
+			{Key: "download-queue-size", Label: P("Active downloads"), Type: SETTING_INT, Min: 1},
+			{Key: "seed-queue-enabled", Label: P("Seed queue"), Type: SETTING_BOOL},
+			{Key: "seed-queue-size", Label: P("Active seeds"), Type: SETTING_INT, Min: 1},
+			{Key: "queue-stalled-enabled", Label: P("Skip stalled torrents"), Type: SETTING_BOOL},
+			{Key: "queue-stalled-minutes", Label: P("Stalled after (minutes)"), Type: SETTING_INT, Min: 1},
+		}},
+		{P("Alternative speed"), []Setting{
+			{Key: "alt-speed-enabled", Label: P("Use alternative speed"), Type: SETTING_BOOL},
+			{Key: "alt-speed-down", Label: P("Download limit (kB/s)"), Type: SETTING_INT},
+			{Key: "alt-speed-up", Label: P("Upload limit (kB/s)"), Type: SETTING_INT},
+			{Key: "alt-speed-time-enabled", Label: P("Scheduled"), Type: SETTING_BOOL},
+			{Key: "alt-speed-time-begin", Label: P("From"), Type: SETTING_TIME},
+			{Key: "alt-speed-time-end", Label: P("To"), Type: SETTING_TIME},
+			{Key: "alt-speed-time-day", Label: P("On days"), Type: SETTING_DAYS},
+		}},
+		{P("Seeding"), []Setting{
+			{Key: "seedRatioLimited", Label: P("Stop at ratio"), Type: SETTING_BOOL},
+			{Key: "seedRatioLimit", Label: P("Ratio limit"), Type: SETTING_FLOAT},
+			{Key: "idle-seeding-limit-enabled", Label: P("Stop when idle"), Type: SETTING_BOOL},
+			{Key: "idle-seeding-limit", Label: P("Idle limit (minutes)"), Type: SETTING_INT, Min: 1},
+		}},
+		{P("Files"), []Setting{
+			{Key: "download-dir", Label: P("Download dir"), Type: SETTING_TEXT, Min: 1},
+			{Key: "incomplete-dir-enabled", Label: P("Use incomplete dir"), Type: SETTING_BOOL},
+			{Key: "incomplete-dir", Label: P("Incomplete dir"), Type: SETTING_TEXT, Min: 1},
+			{Key: "rename-partial-files", Label: P("Append .part to incomplete files"), Type: SETTING_BOOL},
+			{Key: "start-added-torrents", Label: P("Start added torrents"), Type: SETTING_BOOL},
+			{Key: "trash-original-torrent-files", Label: P("Delete added .torrent files"), Type: SETTING_BOOL},
+		}},
+	}
+}
+
+// Arguments of torrent-get/torrent-set shown by ShowTorrentSettings.
+func TorrentSettings() []Setting {
+	return []Setting{
+		{Key: "downloadLimited", Label: P("Limit download speed"), Type: SETTING_BOOL},
+		{Key: "downloadLimit", Label: P("Download limit (kB/s)"), Type: SETTING_INT},
+		{Key: "uploadLimited", Label: P("Limit upload speed"), Type: SETTING_BOOL},
+		{Key: "uploadLimit", Label: P("Upload limit (kB/s)"), Type: SETTING_INT},
+		{Key: "honorsSessionLimits", Label: P("Honor session limits"), Type: SETTING_BOOL},
+		{Key: "bandwidthPriority", Label: P("Bandwidth priority"), Type: SETTING_MODE, Min: -1,
+			Choices: []string{P("Low"), P("Normal"), P("High")}},
+		{Key: "peer-limit", Label: P("Peer limit"), Type: SETTING_INT, Min: 1},
+		{Key: "seedRatioMode", Label: P("Stop at ratio"), Type: SETTING_MODE,
+			Choices: []string{P("Session default"), P("Ratio limit"), P("Unlimited")}},
+		{Key: "seedRatioLimit", Label: P("Ratio limit"), Type: SETTING_FLOAT},
+		{Key: "seedIdleMode", Label: P("Stop when idle"), Type: SETTING_MODE,
+			Choices: []string{P("Session default"), P("Idle limit"), P("Unlimited")}},
+		{Key: "seedIdleLimit", Label: P("Idle limit (minutes)"), Type: SETTING_INT, Min: 1},
+	}
+}
+
+// Show the session settings starting with the named page, the fields of
+// each page are sent with session-set when they are saved.
+func ShowSettings(page string) {
+	var session map[string]interface{}
+	Async(func() error {
+		return Client.SessionGet(&session)
+	}, func() {
+		if App.GetFocus() == MainList {
+			ShowSettingsPages(session, page)
+		}
+	})
+}
+
+func ShowSettingsPages(session map[string]interface{}, page string) {
+	ViewOpen = true
+	MainGrid.RemoveItem(MainList)
+	keys := []Key{{"Esc", P("Close")}, {"Enter", P("Edit")},
+		{"Tab", P("Next field")}, {"F2", P("Save")}}
+	SetKeysHeaderText(P("Session settings"), FormatKeys(keys),
+		tview.AlignCenter)
+	list := NewListPrim()
+	pages := tview.NewPages()
+	var forms []*tview.Form
+	var settings [][]Setting
+	for _, p := range SettingsPages() {
+		form, shown := NewSettingsForm(p.Settings, session)
+		if len(shown) == 0 {
+			continue // Not supported by the daemon.
+		}
+		list.AddItem("  "+p.Name, "", 0, nil)
+		pages.AddPage(p.Name, form, true, len(forms) == 0)
+		forms = append(forms, form)
+		settings = append(settings, shown)
+	}
+	list.SetChangedFunc(func(i int, name, _ string, _ rune) {
+		pages.SwitchToPage(strings.TrimSpace(name))
+	})
+	if i := list.FindItems("  "+page, "", false, false); len(i) > 0 {
+		list.SetCurrentItem(i[0])
+	}
+	flex := tview.NewFlex().
+		AddItem(list, 24, 0, true).
+		AddItem(pages, 0, 1, false)
+	endwin := func() {
+		SwitchToMain(flex, LIST)
+		ViewOpen = false
+	}
+	MainGrid.AddItem(flex, 2, 0, 1, 3, 0, 0, true)
+	App.SetFocus(list).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if len(forms) == 0 {
+				if event.Key() == tcell.KeyEsc {
+					endwin()
+				}
+				return event
+			}
+			form := forms[list.GetCurrentItem()]
+			switch event.Key() {
+			case tcell.KeyEsc:
+				if list.HasFocus() {
+					endwin()
+				} else {
+					App.SetFocus(list)
+				}
+				return nil
+			case tcell.KeyEnter, tcell.KeyRight:
+				if list.HasFocus() {
+					App.SetFocus(form)
+					return nil
+				}
+			case tcell.KeyF2:
+				args := make(map[string]interface{})
+				for i, f := range forms {
+					n, err := SettingsChanges(f, settings[i], session, nil,
+						args)
+					if err != nil {
+						ReportError(err)
+						list.SetCurrentItem(i)
+						App.SetFocus(f.SetFocus(n))
+						return nil
+					}
+				}
+				endwin()
+				if len(args) == 0 {
+					return nil
+				}
+				Async(func() error {
+					return Client.SessionSet(args)
+				}, func() {
+					if Alt != nil {
+						SetAltSpeed(Alt, args)
+					}
+					Statusbar.SetText(P("Settings saved"))
+				})
+				return nil
+			}
+			return event
+		})
+}
+
+// Show the limits of the highlighted torrent, the changed ones are applied
+// with torrent-set to it or to the selected torrents.
+func ShowTorrentSettings(item int) {
+	if MainList.GetItemCount() == 0 {
+		return
+	}
+	ids := TargetIds(item)
+	id := GetId(item, MainList)
+	settings := TorrentSettings()
+	fields := []string{"name"}
+	for _, s := range settings {
+		fields = append(fields, s.Key)
+	}
+	var res struct {
+		Torrents []map[string]interface{} `json:"torrents"`
+	}
+	Async(func() error {
+		return Client.TorrentGet([]int{id}, fields, &res)
+	}, func() {
+		if len(res.Torrents) > 0 && App.GetFocus() == MainList {
+			ShowTorrentSettingsForm(ids, res.Torrents[0], settings)
+		}
+	})
+}
+
+func ShowTorrentSettingsForm(ids []int, values map[string]interface{}, settings []Setting) {
+	ViewOpen = true
+	MainGrid.RemoveItem(MainList)
+	title := P("Torrent settings") + ": " + fmt.Sprint(values["name"])
+	if len(ids) > 1 {
+		title = fmt.Sprintf(P("Torrent settings")+": %d "+P("torrents"),
+			len(ids))
+	}
+	keys := []Key{{"Esc", P("Close")}, {"Tab", P("Next field")},
+		{"F2", P("Save")}}
+	SetKeysHeaderText(title, FormatKeys(keys), tview.AlignCenter)
+	form, shown := NewSettingsForm(settings, values)
+	// The values are of the highlighted torrent, the other ones get all
+	// the fields which were edited.
+	var touched map[string]bool
+	if len(ids) > 1 {
+		touched = TrackTouched(form, shown)
+	}
+	endwin := func() {
+		SwitchToMain(form, LIST)
+		ViewOpen = false
+	}
+	MainGrid.AddItem(form, 2, 0, 1, 3, 0, 0, true)
+	App.SetFocus(form).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEsc:
+				endwin()
+				return nil
+			case tcell.KeyF2:
+				args := make(map[string]interface{})
+				n, err := SettingsChanges(form, shown, values, touched, args)
+				if err != nil {
+					ReportError(err)
+					App.SetFocus(form.SetFocus(n))
+					return nil
+				}
+				endwin()
+				if len(args) == 0 {
+					return nil
+				}
+				args["ids"] = ids
+				Async(func() error {
+					return Client.TorrentSet(args)
+				}, func() {
+					Statusbar.SetText(P("Settings saved"))
+				})
+				return nil
+			}
+			return event
+		})
+}
+
+// Make a form with the settings which are in the session arguments.
+func NewSettingsForm(settings []Setting, session map[string]interface{}) (*tview.Form, []Setting) {
+	form := tview.NewForm().
+		SetFieldTextColor(tcell.Color221).
+		SetFieldBackgroundColor(tcell.Color25).
+		SetLabelColor(tcell.ColorDefault)
+	form.SetBackgroundColor(tcell.ColorDefault)
+	var shown []Setting
+	for _, s := range settings {
+		v, ok := session[s.Key]
+		if !ok {
+			continue
+		}
+		switch s.Type {
+		case SETTING_BOOL:
+			b, _ := v.(bool)
+			form.AddCheckbox(s.Label, b, nil)
+		case SETTING_INT, SETTING_FLOAT:
+			f, _ := v.(float64)
+			accept := tview.InputFieldInteger
+			if s.Type == SETTING_FLOAT {
+				accept = tview.InputFieldFloat
+			}
+			form.AddInputField(s.Label,
+				strconv.FormatFloat(f, 'f', -1, 64), 12, accept, nil)
+		case SETTING_TEXT:
+			text, _ := v.(string)
+			form.AddInputField(s.Label, text, 50, nil, nil)
+		case SETTING_CHOICE:
+			text, _ := v.(string)
+			n := 0
+			for i, c := range s.Choices {
+				if c == text {
+					n = i
+				}
+			}
+			form.AddDropDown(s.Label, s.Choices, n, nil)
+		case SETTING_MODE:
+			f, _ := v.(float64)
+			n := int(f - s.Min)
+			if n < 0 || n >= len(s.Choices) {
+				n = 0
+			}
+			form.AddDropDown(s.Label, s.Choices, n, nil)
+		case SETTING_TIME:
+			f, _ := v.(float64)
+			form.AddInputField(s.Label, FormatMinutes(int(f)), 6, nil, nil)
+		case SETTING_DAYS:
+			// A checkbox for each day, the mask is built from them.
+			f, _ := v.(float64)
+			label := s.Label + ": "
+			for i, d := range DayNames() {
+				form.AddCheckbox(label+d, int(f)&(1<<uint(i)) != 0, nil)
+				label = strings.Repeat(" ", utf8.RuneCountInString(label))
+				day := s
+				day.Type, day.Day = SETTING_DAY, i
+				shown = append(shown, day)
+			}
+			continue
+		}
+		shown = append(shown, s)
+	}
+	return form, shown
+}
+
+// Record the keys of the fields edited by the user.
+func TrackTouched(form *tview.Form, settings []Setting) map[string]bool {
+	touched := make(map[string]bool)
+	for i, s := range settings {
+		key := s.Key
+		switch item := form.GetFormItem(i).(type) {
+		case *tview.Checkbox:
+			item.SetChangedFunc(func(bool) { touched[key] = true })
+		case *tview.DropDown:
+			item.SetSelectedFunc(func(string, int) { touched[key] = true })
+		case *tview.InputField:
+			item.SetChangedFunc(func(string) { touched[key] = true })
+		}
+	}
+	return touched
+}
+
+// Add the changed values of the form and those of the touched keys to
+// args. On an invalid value return the index of its field.
+func SettingsChanges(form *tview.Form, settings []Setting, session map[string]interface{}, touched map[string]bool, args map[string]interface{}) (int, error) {
+	days := make(map[string]int)
+	for i, s := range settings {
+		var v interface{}
+		switch item := form.GetFormItem(i).(type) {
+		case *tview.Checkbox:
+			v = item.IsChecked()
+			if s.Type == SETTING_DAY {
+				if item.IsChecked() {
+					days[s.Key] |= 1 << uint(s.Day)
+				} else {
+					days[s.Key] |= 0
+				}
+				continue
+			}
+		case *tview.DropDown:
+			n, text := item.GetCurrentOption()
+			v = text
+			if s.Type == SETTING_MODE {
+				v = n + int(s.Min)
+			}
+		case *tview.InputField:
+			text := strings.TrimSpace(item.GetText())
+			var err error
+			if v, err = ParseSetting(s, text); err != nil {
+				return i, err
+			}
+		}
+		if prev, ok := session[s.Key]; !ok || touched[s.Key] ||
+			fmt.Sprint(prev) != fmt.Sprint(v) {
+			args[s.Key] = v
+		}
+	}
+	for key, mask := range days {
+		if touched[key] || fmt.Sprint(session[key]) != fmt.Sprint(mask) {
+			args[key] = mask
+		}
+	}
+	return 0, nil
+}
+
+// Check the text of a field and convert it to the argument value.
+func ParseSetting(s Setting, text string) (interface{}, error) {
+	invalid := errors.New(P("Invalid value") + ": " + s.Label)
+	var f float64
+	switch s.Type {
+	case SETTING_TEXT:
+		if len(text) < int(s.Min) {
+			return nil, invalid
+		}
+		return text, nil
+	case SETTING_TIME:
+		m, err := ParseMinutes(text)
+		if err != nil {
+			return nil, invalid
+		}
+		return m, nil
+	case SETTING_INT:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, invalid
+		}
+		f = float64(n)
+	default:
+		var err error
+		if f, err = strconv.ParseFloat(text, 64); err != nil {
+			return nil, invalid
+		}
+	}
+	if f < s.Min || s.Max > 0 && f > s.Max {
+		return nil, invalid
+	}
+	if s.Type == SETTING_INT {
+		return int(f), nil
+	}
+	return f, nil
+}
+
+// Get the alternative speed settings.
+func GetAltSpeed() (*AltSpeed, error) {
+	a := &AltSpeed{}
+	err := Client.Call("session-get",
+		&rpc.SessionGetArgs{Fields: AltSpeedFields}, a)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Update a with the alt-speed arguments sent with session-set.
+func SetAltSpeed(a *AltSpeed, args map[string]interface{}) {
+	data, err := json.Marshal(args)
+	if err == nil {
+		json.Unmarshal(data, a)
+	}
+	ShowStatusbar()
+}
+
+// Turn the alternative speed limits on or off.
+func ToggleAltSpeed() {
+	if Alt == nil {
+		return
+	}
+	args := map[string]interface{}{"alt-speed-enabled": !Alt.Enabled}
+	Async(func() error {
+		return Client.SessionSet(args)
+	}, func() {
+		SetAltSpeed(Alt, args)
+	})
+}
+
+// Status bar text of the alternative speed.
+func FormatAltSpeed(a *AltSpeed) string {
+	var s string
+	if a == nil {
+		return s
+	}
+	if a.Enabled {
+		s = fmt.Sprintf("| [yellow:]"+P("Alt speed")+": %d/%d "+
+			P("kB/s")+"[-:] ", a.Down, a.Up)
+	}
+	if a.TimeEnabled {
+		s += "| " + P("Schedule") + ": " + FormatMinutes(a.Begin) +
+			"-" + FormatMinutes(a.End) + " " + FormatDays(a.Day)
+	}
+	return s
+}
+
+func DayNames() []string {
+	return []string{P("Sun"), P("Mon"), P("Tue"), P("Wed"), P("Thu"),
+		P("Fri"), P("Sat")}
+}
+
+// Names and masks of the schedule days.
+func DayPresets() ([]string, []int) {
+	names := []string{P("Every day"), P("Weekdays"), P("Weekend")}
+	masks := []int{DAYS_ALL, DAYS_WEEKDAYS, DAYS_WEEKEND}
+	for i, d := range DayNames() {
+		names = append(names, d)
+		masks = append(masks, 1<<uint(i))
+	}
+	return names, masks
+}
+
+// Readable form of a day mask, e.g. "Weekend" or "Mon, Wed".
+func FormatDays(mask int) string {
+	names, masks := DayPresets()
+	for i, m := range masks {
+		if m == mask {
+			return names[i]
+		}
+	}
+	var days []string
+	for i, d := range DayNames() {
+		if mask&(1<<uint(i)) != 0 {
+			days = append(days, d)
+		}
+	}
+	return strings.Join(days, ", ")
+}
+
+func FormatMinutes(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
+
+// Parse HH:MM to minutes since midnight.
+func ParseMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
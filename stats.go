@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Show the transfer totals of the daemon, the number of torrents in each
+// status and the free space of the download dirs.
+func ShowSessionStats() {
+	var stats *SessionStats
+	var dirs, avail []string
+	Async(func() error {
+		var err error
+		if stats, err = GetSessionStats(); err != nil {
+			return err
+		}
+		if dirs, err = GetDownloadDirs(); err != nil {
+			return err
+		}
+		for _, d := range dirs {
+			avail = append(avail, DiskAvail(d))
+		}
+		return nil
+	}, func() {
+		if App.GetFocus() == MainList {
+			Stats = stats
+			ShowSessionStatsText(stats, dirs, avail)
+		}
+	})
+}
+
+func ShowSessionStatsText(s *SessionStats, dirs, avail []string) {
+	ViewOpen = true
+	MainGrid.RemoveItem(MainList)
+	keys := []Key{{"Esc", P("Close")}}
+	SetKeysHeaderText(P("Session statistics"), FormatKeys(keys),
+		tview.AlignCenter)
+	pre := [...]string{P("Uploaded"), P("Downloaded"), P("Ratio"),
+		P("Files added"), P("Sessions"), P("Time active")}
+	n := 0
+	for _, p := range pre {
+		if l := utf8.RuneCountInString(p); l > n {
+			n = l
+		}
+	}
+	cur, all := &s.Current, &s.Cumulative
+	rows := [][2]string{
+		{FormatSize(cur.Uploaded), FormatSize(all.Uploaded)},
+		{FormatSize(cur.Downloaded), FormatSize(all.Downloaded)},
+		{FormatTotalRatio(cur), FormatTotalRatio(all)},
+		{fmt.Sprint(cur.FilesAdded), fmt.Sprint(all.FilesAdded)},
+		{fmt.Sprint(cur.Sessions), fmt.Sprint(all.Sessions)},
+		{FormatDuration(cur.Seconds), FormatDuration(all.Seconds)},
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%*s  %-16s %s[::-]\n", n, "",
+		P("Current session"), P("Total"))
+	for i, r := range rows {
+		fmt.Fprintf(&b, "%*s: %-16s %s\n", n, pre[i], r[0], r[1])
+	}
+	fmt.Fprintf(&b, "\n[::b]%s[::-]\n", P("Torrents"))
+	st, count := CountStatus()
+	for _, k := range st {
+		fmt.Fprintf(&b, "%*s: %d\n", n, k, count[k])
+	}
+	fmt.Fprintf(&b, "\n[::b]%s[::-]\n", P("Download dirs"))
+	for i, d := range dirs {
+		fmt.Fprintf(&b, "%s%s\n", tview.Escape(d), avail[i])
+	}
+	text := NewTextPrim(b.String())
+	MainGrid.AddItem(text, 2, 0, 1, 3, 0, 0, true)
+	App.SetFocus(text).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEsc:
+				text.Clear()
+				SwitchToMain(text, LIST)
+				ViewOpen = false
+			}
+			return event
+		})
+}
+
+func FormatTotalRatio(t *StatsTotals) string {
+	if t.Downloaded == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", float64(t.Uploaded)/float64(t.Downloaded))
+}
+
+// Format seconds as days, hours and minutes.
+func FormatDuration(seconds int64) string {
+	d, h, m := seconds/86400, seconds%86400/3600, seconds%3600/60
+	switch {
+	case d > 0:
+		return fmt.Sprintf("%d"+P("d")+" %d"+P("h"), d, h)
+	case h > 0:
+		return fmt.Sprintf("%d"+P("h")+" %d"+P("m"), h, m)
+	}
+	return fmt.Sprintf("%d"+P("m"), m)
+}
@@ -4,29 +4,32 @@ package main
 
 import (
 	"bufio"
-	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"net/http"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
-	"reflect"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 	"unicode/utf8"
-	"unsafe"
 
 	"github.com/famz/SetLocale"
 	"github.com/gdamore/tcell/v2"
-	"github.com/marksamman/bencode"
 	"github.com/rivo/tview"
+	"github.com/takiz/trango/metainfo"
+	"github.com/takiz/trango/rpc"
 	"golang.org/x/sys/unix"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -38,6 +41,8 @@ const (
 	DEFAULT_HOST = "127.0.0.1"
 	DEFAULT_PORT = "9091"
 	DEFAULT_URL  = "/transmission/rpc"
+
+	MAX_RETRY_DELAY = 64 * time.Second
 )
 
 const (
@@ -88,6 +93,22 @@ const (
 	SAVE // To save a path/category.
 )
 
+// Sort orders of the main list.
+const (
+	SORT_DATE = iota
+	SORT_NAME
+	SORT_PROGRESS
+	SORT_SIZE
+	SORT_QUEUE
+)
+
+// How AddTorrent sends a local torrent file.
+const (
+	METAINFO_AUTO   = "auto" // Contents for a remote daemon, path otherwise
+	METAINFO_ALWAYS = "always"
+	METAINFO_NEVER  = "never"
+)
+
 // For printing hotkeys.
 type Key struct {
 	Name string
@@ -106,23 +127,9 @@ type StatusSymbol struct {
 
 // fmt.Sprintf("%*s") offset.
 type StatFormat struct {
-	Eta  int
-	Done int
-}
-
-type Request struct {
-	Method string      `json:"method"`
-	Args   interface{} `json:"arguments"`
-}
-
-type Response struct {
-	Args   interface{} `json:"arguments"`
-	Result string      `json:"result"`
-}
-
-type Arg struct {
-	Fields []string `json:"fields,omitempty"`
-	Ids    []int    `json:"ids,omitempty"`
+	Eta   int
+	Done  int
+	Queue int
 }
 
 type Torrent struct {
@@ -138,6 +145,9 @@ type Torrent struct {
 	DlSpeed  int     `json:"rateDownload,omitempty"`
 	UplSpeed int     `json:"rateUpload,omitempty"`
 	Error    int     `json:"error,omitempty"`
+	Eta      int64   `json:"eta,omitempty"`
+	Peers    int     `json:"peersConnected,omitempty"`
+	Queue    int     `json:"queuePosition,omitempty"`
 }
 
 type TorrentInfo struct {
@@ -150,6 +160,7 @@ type TorrentInfo struct {
 	Status   int     `json:"status,omitempty"`
 	UplSpeed int     `json:"rateUpload,omitempty"`
 	Error    int     `json:"error,omitempty"`
+	Queue    int     `json:"queuePosition,omitempty"`
 }
 
 type GeneralInfo struct {
@@ -174,6 +185,17 @@ type SessionStats struct {
 	TorrentCount       int `json:"torrentCount,omitempty"`
 	DownloadSpeed      int `json:"downloadSpeed,omitempty"`
 	UploadSpeed        int `json:"uploadSpeed,omitempty"`
+
+	Cumulative StatsTotals `json:"cumulative-stats"`
+	Current    StatsTotals `json:"current-stats"` // Since the daemon started
+}
+
+type StatsTotals struct {
+	Uploaded   int64 `json:"uploadedBytes,omitempty"`
+	Downloaded int64 `json:"downloadedBytes,omitempty"`
+	FilesAdded int   `json:"filesAdded,omitempty"`
+	Sessions   int   `json:"sessionCount,omitempty"`
+	Seconds    int64 `json:"secondsActive,omitempty"`
 }
 
 type PeersInfo struct {
@@ -189,6 +211,11 @@ type TorrentsGet struct {
 	All []*Torrent `json:"torrents"`
 }
 
+// Fields of TorrentInfo.
+var InfoFields = []string{"id", "sizeWhenDone", "error", "percentDone",
+	"status", "peersConnected", "rateDownload", "rateUpload", "eta",
+	"queuePosition"}
+
 type TorrentsGetInfo struct {
 	All []*TorrentInfo `json:"torrents"`
 }
@@ -258,90 +285,134 @@ type TreeFiles struct {
 }
 
 var (
-	URL                      string
-	HeaderId                 string
-	AuthUsername, AuthPasswd string
-	TransmissionVersion      int
-	UpdateInt                time.Duration // Update info interval in seconds
-	Torrents                 []*Torrent
-	Stats                    *SessionStats
-	Contents                 []Content
-	ContentsTree             []Content
-	FilePath                 string
-	SelectedIds              map[int]int
-	CurrentCategory          string
-	CurrentStatus            CurrStatus
-	Status                   map[string]int
-	Category                 map[string]int
-	Dirs                     map[string]int
-	Title                    string
-	TitleStatus              string
-	MainKeysText             string
-	SelectedFileIds          map[string]*FileType
-	FilesAll                 []interface{}
-	TotalSize                int64 // Current size of files in ShowAddDialog()
-	StatSymb                 *StatusSymbol
-	ALL, DEFAULT             string // Status/category names
-	StatFmt                  = StatFormat{Eta: 6, Done: 7}
-	RuneDir                  = string('\u23f7') + " "
-	RuneLTee                 = " " + string(tcell.RuneLTee) + string(tcell.RuneHLine)
-	RuneLTeeDir              = RuneLTee + RuneDir
-	RuneLLCorner             = " " + string(tcell.RuneLLCorner) + string(tcell.RuneHLine) + " "
-	RuneVLine                = "  " + string(tcell.RuneVLine)
-	App                      *tview.Application
-	Statusbar                *tview.TextView
-	Header                   *tview.TextView
-	Hotkeys                  *tview.TextView
-	Peers                    *tview.TextView
-	CategoryStatus           *tview.TextView
-	SaveTo                   *tview.TextView
-	CategoryName             *tview.TextView
-	MainGrid                 *tview.Grid
-	MainList                 *tview.List
-	MainMutex                sync.Mutex
-	PeersMutex               sync.Mutex
+	Client          *rpc.Client
+	Cfg             *Config
+	Features        rpc.Features  // Of the connected daemon
+	UpdateInt       time.Duration // Update info interval in seconds
+	ResyncCycles    int           // Updates between full reloads
+	Resync          int32         // Set to reload all torrents on the next update
+	SendMetainfo    string        // METAINFO_* mode of AddTorrent
+	SortOrder       int
+	Torrents        []*Torrent
+	TorrentById     map[int]*Torrent
+	Rows            []int       // Torrent ids of the MainList rows
+	RowById         map[int]int // MainList rows of the torrent ids
+	Stats           *SessionStats
+	Alt             *AltSpeed
+	Contents        []Content
+	ContentsTree    []Content
+	FilePath        string
+	SelectedIds     map[int]int
+	CurrentCategory string
+	CurrentStatus   CurrStatus
+	Status          map[string]int
+	Category        map[string]int
+	Dirs            []string // Download dirs shown by the add dialog.
+	BrowseDir       string   // Current dir of the file browser.
+	Title           string
+	TitleStatus     string
+	MainKeysText    string
+	SelectedFileIds map[string]*FileType
+	FilePriorities  map[int]int // File index to -1 (low), 0 or 1 (high)
+	FilesAll        []metainfo.File
+	TotalSize       int64 // Current size of files in ShowAddDialog()
+	StatSymb        *StatusSymbol
+	ALL, DEFAULT    string // Status/category names
+	StatFmt         = StatFormat{Eta: 6, Done: 7, Queue: 4}
+	RuneDir         = string('\u23f7') + " "
+	RuneLTee        = " " + string(tcell.RuneLTee) + string(tcell.RuneHLine)
+	RuneLTeeDir     = RuneLTee + RuneDir
+	RuneLLCorner    = " " + string(tcell.RuneLLCorner) + string(tcell.RuneHLine) + " "
+	RuneVLine       = "  " + string(tcell.RuneVLine)
+	App             *tview.Application
+	Statusbar       *tview.TextView
+	Header          *tview.TextView
+	Hotkeys         *tview.TextView
+	Peers           *tview.TextView
+	CategoryStatus  *tview.TextView
+	SaveTo          *tview.TextView
+	SaveToDir       string // Path shown by SaveTo.
+	CategoryName    *tview.TextView
+	MainGrid        *tview.Grid
+	MainList        *tview.List
+	ViewOpen        bool // A view replaces MainList, its updates are paused.
+	RefreshNow      = make(chan bool, 1)
 )
 
 func SetOpts() {
-	host := flag.String("host", DEFAULT_HOST, P("Set host"))
-	port := flag.String("port", DEFAULT_PORT, P("Set port"))
+	flag.String("host", DEFAULT_HOST, P("Set host"))
+	flag.String("port", DEFAULT_PORT, P("Set port"))
+	flag.String("url", "", P("<URL>  Set full RPC URL, e.g. https://host/transmission/rpc (overrides -host and -port)"))
+	flag.String("cacert", "", P("<file>  Verify the daemon with CA certificates from the PEM file"))
+	flag.String("cert", "", P("<file>  Client certificate for TLS authentication"))
+	flag.String("key", "", P("<file>  Private key of the client certificate"))
+	flag.Bool("insecure", false, P("Do not verify the daemon TLS certificate"))
 	dir := flag.String("dir", "", P("<path>  Set download dir when adding a new torrent"))
 	ctg := flag.String("category", "", P("<name1,name2,...>  Set categories when adding a new torrent"))
-	filename := flag.String("add", "", P("<filename-or-URL>  Add torrent"))
+	filename := flag.String("add", "", P("<filename-or-URL>  Add torrent, more can follow as arguments, - reads them from standard input"))
 	files := flag.String("files", "", P("<0,1,2,3,...> Mark files for download by index numbers"))
-	user := flag.String("user", "", P("Set username"))
-	pass := flag.String("pass", "", P("Set password"))
+	flag.String("user", "", P("Set username"))
+	flag.String("pass", "", P("Set password (prefer TRANGO_PASS, netrc or pass_command)"))
 	ascii := flag.Bool("ascii", false, P("Show full status names"))
 	start := flag.Bool("start", false, P("Start added torrent"))
-	dialog := flag.Bool("dialog", false, P("Show dialog when adding a new torrent file (not url/magnet)"))
+	dialog := flag.Bool("dialog", false, P("Show dialog when adding a new torrent"))
+	priority := flag.String("priority", "", P("<low|normal|high>  Set bandwidth priority when adding a new torrent"))
+	peerLimit := flag.Int("peer-limit", 0, P("<n>  Set peer limit when adding a new torrent"))
+	sendMeta := flag.String("metainfo", METAINFO_AUTO, P("<auto|always|never>  Send the contents of a torrent file instead of its path, auto does it for a remote daemon"))
+	browse := flag.String("browse", "", P("<dir>  Start dir of the file browser for adding torrents, ~/Downloads by default"))
+	infoFile := flag.String("info", "", P("<file>  Print the contents of a torrent file, the daemon is not needed"))
+	asJSON := flag.Bool("json", false, P("Print -info as JSON"))
 	trackers := flag.Bool("trackers", false, P("Print tracker URLs of a torrent file to standard output"))
 	interval := flag.Int("update", 2, P("Set the interval for updating torrents information in seconds"))
+	resync := flag.Int("resync", 30, P("<n>  Reload all torrents every n updates, only changed ones are fetched otherwise"))
 	version := flag.Bool("version", false, P("Print current version"))
+	config := flag.String("config", "", P("<filename>  Use an alternate config file"))
+	profile := flag.String("profile", "", P("<name>  Connect with a profile from the config file"))
 
 	flag.Parse()
-	URL = "http://" + *host + ":" + *port + DEFAULT_URL
-	if *user != "" || *pass != "" {
-		u := make([]byte, len(*user))
-		copy(u, *user)
-		p := make([]byte, len(*pass))
-		copy(p, *pass)
-		AuthUsername = string(u)
-		AuthPasswd = string(p)
-		for j, ar := range os.Args[1:] {
-			if ar == "-user" || ar == "-pass" {
-				s := os.Args[j+2]
-				max := len(s)
-				for i := 0; i < max; i++ {
-					h := (*reflect.StringHeader)(unsafe.Pointer(&s))
-					a := (*int8)(unsafe.Pointer(h.Data + uintptr(i)))
-					*a = '*'
-				}
-			}
+	if *infoFile != "" {
+		if err := PrintTorrentInfo(*infoFile, *asJSON); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+	cfg, err := LoadConfig(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	Cfg = cfg
+	if *profile == "" {
+		*profile = cfg.ProfileName
+	}
+	SaveCmdFlags()
+	conn, err := cfg.Connection(*profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.ProfileName = *profile
+	if err = cfg.ApplyFlags(); err != nil {
+		log.Fatal(err)
+	}
+	SortOrder = SORT_NAME
+	if cfg.SortBy != "" {
+		if SortOrder, err = ParseSortOrder(cfg.SortBy); err != nil {
+			log.Fatal(err)
 		}
 	}
 
 	SelectedIds = make(map[int]int)
+	BrowseDir = DefaultBrowseDir()
+	if *browse != "" {
+		BrowseDir = filepath.Clean(ExpandHome(*browse))
+	}
 	UpdateInt = time.Duration(*interval)
+	ResyncCycles = *resync
+	switch *sendMeta {
+	case METAINFO_AUTO, METAINFO_ALWAYS, METAINFO_NEVER:
+		SendMetainfo = *sendMeta
+	default:
+		log.Fatal(P("Invalid value") + ": -metainfo " + *sendMeta)
+	}
 	ALL = P("All")
 	DEFAULT = P("Default")
 	CurrentCategory = ALL
@@ -389,9 +460,19 @@ func SetOpts() {
 	if l > StatFmt.Eta {
 		StatFmt.Eta = l
 	}
+	queue := P("Queue")
+	l = utf8.RuneCountInString(queue)
+	if l > StatFmt.Queue {
+		StatFmt.Queue = l
+	}
+	// The status symbols have no title.
+	queueTitle := fmt.Sprintf(" %*s |", StatFmt.Queue, queue)
+	if TitleStatus == "" {
+		queueTitle = "    " + queueTitle
+	}
 	Title = fmt.Sprintf("%*s ", StatFmt.Eta, eta) + P("|  Uploading  |"+
 		" Downloading | Peers |  Done  |   Size    |") +
-		TitleStatus + P("   Name ")
+		TitleStatus + queueTitle + P("   Name ")
 
 	MainKeysText = FormatKeys([]Key{{"F1", P("Help")}, {"F2", P("Status")},
 		{"F3", P("Category")}, {"F4", P("General")}, {"F5", P("Trackers")},
@@ -402,47 +483,100 @@ func SetOpts() {
 		fmt.Println(VERSION)
 		os.Exit(0)
 	}
+	// More sources can follow -add only.
+	var sources []string
 	if *filename != "" {
-		var notUrl bool
-		if !strings.HasPrefix(*filename, "http") &&
-			!strings.HasPrefix(*filename, "magnet") {
-			notUrl = true
-			if !strings.HasPrefix(*filename, "/") {
-				pwd, err := os.Getwd()
-				if err != nil {
-					log.Fatal(err)
+		sources = append([]string{*filename}, flag.Args()...)
+	}
+	for _, s := range flag.Args() {
+		// The flags after the first argument are not parsed.
+		if *filename != "" && strings.HasPrefix(s, "-") && s != "-" {
+			log.Fatal(P("The flags must come before the torrents") + ": " + s)
+		}
+	}
+	if len(sources) == 0 {
+		if Client, err = conn.NewClient(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if sources, err = ExpandSources(sources); err != nil {
+		log.Fatal(err)
+	}
+	if *trackers {
+		for _, s := range sources {
+			info, err := metainfo.ParseFile(s)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, tier := range info.Trackers() {
+				for _, t := range tier {
+					fmt.Fprintf(os.Stdout, "%s\n", t)
 				}
-				*filename = pwd + "/" + *filename
 			}
 		}
-		if *trackers {
-			_, _, _, tr := ParseTorrent(filename)
-			for _, t := range tr {
-				fmt.Fprintf(os.Stdout, "%s\n", t)
-			}
+		os.Exit(0)
+	}
+	opts := AddOptions{Files: *files, Category: *ctg, Dir: *dir,
+		Start: *start, Dialog: *dialog, PeerLimit: *peerLimit}
+	if opts.Priority, err = ParsePriority(*priority); err != nil {
+		log.Fatal(err)
+	}
+	if opts.Dialog {
+		// The running interface needs no connection of its own.
+		if sources = HandOff(sources, opts); len(sources) == 0 {
 			os.Exit(0)
 		}
-		var cancelDlg bool
-		if *dialog && notUrl {
-			ShowAddDialog(filename, files, ctg, dir, start, &cancelDlg)
-		}
-		paused := true
-		if *start {
-			paused = false
-		}
-		if !cancelDlg {
-			AddTorrent(*filename, *dir, *ctg, *files, paused)
-		}
-		os.Exit(0)
 	}
+	if Client, err = conn.NewClient(); err != nil {
+		log.Fatal(err)
+	}
+	if Features, err = GetFeatures(Client); err != nil {
+		Fatal(err)
+	}
+	if AddAll(sources, opts) > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// Make the RPC endpoint from -url or from -host and -port.
+func MakeURL(rawurl, host, port string) (string, error) {
+	if rawurl == "" {
+		return "http://" + net.JoinHostPort(host, port) + DEFAULT_URL, nil
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return "", errors.New(P("Invalid URL") + ": " + rawurl)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = DEFAULT_URL
+	}
+	return u.String(), nil
 }
 
 func main() {
 	SetLocales()
 	SetOpts()
-	GetSessionStats()
-	TransmissionVersion = GetVersion()
-	GetTorrents()
+	var err error
+	if Stats, err = GetSessionStats(); err != nil {
+		Fatal(err)
+	}
+	if Alt, err = GetAltSpeed(); err != nil {
+		Fatal(err)
+	}
+	if Features, err = GetFeatures(Client); err != nil {
+		Fatal(err)
+	}
+	torrents, err := GetTorrents()
+	if err != nil {
+		Fatal(err)
+	}
+	SetTorrents(torrents)
+	SortBy(SortOrder)
 
 	CategoryStatus = NewTextPrim(PrintCtgStat())
 	Header = NewTextPrim(Title)
@@ -467,13 +601,22 @@ func main() {
 	App = tview.NewApplication().SetRoot(MainGrid, true)
 	App.SetBeforeDrawFunc(func(s tcell.Screen) bool {
 		s.Clear()
+		RenderRows()
 		return false
 	})
 	SetMainInput()
+	listener, err := ListenAdd()
+	if err != nil {
+		ReportError(err)
+	}
 	go ShowCurrent()
+	go Spinner()
 	if err := App.Run(); err != nil {
 		panic(err)
 	}
+	if listener != nil {
+		listener.Close()
+	}
 }
 
 func SetMainInput() {
@@ -486,7 +629,7 @@ func SetMainInput() {
 			case tcell.KeyF2:
 				ShowStatusInfo()
 			case tcell.KeyF3:
-				if TransmissionVersion < 3 {
+				if !Features.Labels {
 					ShowVersionInfo(MainList, LIST, App.GetInputCapture())
 				} else {
 					ShowCategoryInfo()
@@ -494,7 +637,6 @@ func SetMainInput() {
 			case tcell.KeyF4:
 				ShowGeneralInfo(MainList.GetCurrentItem())
 			case tcell.KeyF5:
-				MainMutex.Lock()
 				ShowTrackersInfo(MainList, 0)
 			case tcell.KeyF6:
 				ShowPeersInfo(MainList.GetCurrentItem())
@@ -511,6 +653,10 @@ func SetMainInput() {
 				SortTorrents()
 			case tcell.KeyCtrlL:
 				ShowInputField(MainList, TORRENT_RENAME, nil)
+			case tcell.KeyCtrlT:
+				ShowProfiles()
+			case tcell.KeyCtrlE:
+				ShowSettings("")
 			case tcell.KeyCtrlP:
 				TorAction(MainList.GetCurrentItem(), "torrent-stop", true)
 			case tcell.KeyCtrlS:
@@ -530,7 +676,7 @@ func SetMainInput() {
 			case tcell.KeyEsc:
 				SelectAll(MainList, false)
 			case tcell.KeyCtrlN:
-				if TransmissionVersion < 3 {
+				if !Features.Labels {
 					ShowVersionInfo(MainList, LIST, App.GetInputCapture())
 				} else {
 					ShowInputField(MainList, CATEGORY, nil)
@@ -542,12 +688,36 @@ func SetMainInput() {
 			case tcell.KeyEnter:
 				id := GetId(MainList.GetCurrentItem(), MainList)
 				PreviewFile(id)
+			case tcell.KeyUp, tcell.KeyDown, tcell.KeyHome, tcell.KeyEnd:
+				if event.Modifiers()&tcell.ModAlt == 0 {
+					break
+				}
+				method := map[tcell.Key]string{
+					tcell.KeyUp:   "queue-move-up",
+					tcell.KeyDown: "queue-move-down",
+					tcell.KeyHome: "queue-move-top",
+					tcell.KeyEnd:  "queue-move-bottom",
+				}[event.Key()]
+				QueueMove(MainList.GetCurrentItem(), method)
+				return nil
 			case tcell.KeyRune:
 				switch event.Rune() {
 				case ' ': // space
 					SelectItem(MainList)
 				case '~': // ~ or shift+delete
 					ShowConfirmation("torrent(s)", "torrent-remove", true)
+				case 't':
+					ToggleAltSpeed()
+				case 'T':
+					ShowSettings(P("Alternative speed"))
+				case 'o':
+					ShowTorrentSettings(MainList.GetCurrentItem())
+				case 's':
+					TorAction(MainList.GetCurrentItem(), "torrent-start-now", true)
+				case 'i':
+					ShowSessionStats()
+				case 'a':
+					ShowAddBrowser(nil)
 				}
 			}
 			return event
@@ -604,7 +774,7 @@ func NewListPrim() *tview.List {
 }
 
 func SortTorrents() {
-	MainMutex.Lock()
+	ViewOpen = true
 	MainGrid.RemoveItem(MainList)
 	keys := []Key{{"Esc", P("Close")}, {"Enter", P("Sort")}}
 	SetKeysHeaderText(P("Sort by"), FormatKeys(keys), tview.AlignCenter)
@@ -612,12 +782,13 @@ func SortTorrents() {
 		AddItem(P("   Added Date"), "", 0, nil).
 		AddItem(P("   Name"), "", 0, nil).
 		AddItem(P("   Progress"), "", 0, nil).
-		AddItem(P("   Size"), "", 0, nil)
+		AddItem(P("   Size"), "", 0, nil).
+		AddItem(P("   Queue"), "", 0, nil)
 
 	endwin := func() {
 		list.Clear()
 		SwitchToMain(list, LIST)
-		MainMutex.Unlock()
+		ViewOpen = false
 	}
 	MainGrid.AddItem(list, 2, 0, 1, 3, 0, 0, true)
 	App.SetFocus(list).
@@ -626,25 +797,8 @@ func SortTorrents() {
 			case tcell.KeyEsc:
 				endwin()
 			case tcell.KeyEnter:
-				item := list.GetCurrentItem()
-				switch item {
-				case 0:
-					sort.Slice(Torrents, func(i, j int) bool {
-						return Torrents[i].Date > Torrents[j].Date
-					})
-				case 1:
-					sort.Slice(Torrents, func(i, j int) bool {
-						return strings.ToLower(Torrents[i].Name) < strings.ToLower(Torrents[j].Name)
-					})
-				case 2:
-					sort.Slice(Torrents, func(i, j int) bool {
-						return Torrents[i].Progress > Torrents[j].Progress
-					})
-				case 3:
-					sort.Slice(Torrents, func(i, j int) bool {
-						return Torrents[i].Size > Torrents[j].Size
-					})
-				}
+				SortOrder = list.GetCurrentItem()
+				SortBy(SortOrder)
 				MainList.Clear()
 				InitMainList()
 				endwin()
@@ -653,14 +807,190 @@ func SortTorrents() {
 		})
 }
 
+func ShowProfiles() {
+	names := Cfg.ProfileNames()
+	if len(names) == 0 {
+		Statusbar.SetText(P("No profiles in the config file"))
+		return
+	}
+	ViewOpen = true
+	MainGrid.RemoveItem(MainList)
+	keys := []Key{{"Esc", P("Close")}, {"Enter", P("Connect")}}
+	SetKeysHeaderText(P("Profiles"), FormatKeys(keys), tview.AlignCenter)
+	list := NewListPrim()
+	for _, k := range names {
+		mark := "  "
+		if k == Cfg.ProfileName {
+			mark = "* "
+		}
+		addr := ""
+		p, err := Cfg.Connection(k)
+		if err == nil {
+			addr, err = p.Endpoint()
+		}
+		if err != nil {
+			addr = err.Error()
+		}
+		list.AddItem(fmt.Sprintf("  %s%s  (%s)", mark, k, addr), k, 0, nil)
+	}
+	endwin := func() {
+		list.Clear()
+		SwitchToMain(list, LIST)
+		ViewOpen = false
+	}
+	MainGrid.AddItem(list, 2, 0, 1, 3, 0, 0, true)
+	App.SetFocus(list).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEsc:
+				endwin()
+			case tcell.KeyEnter:
+				_, name := list.GetItemText(list.GetCurrentItem())
+				endwin()
+				SwitchProfile(name)
+			}
+			return event
+		})
+}
+
+// Connect to the daemon of the profile and reload all torrents.
+func SwitchProfile(name string) {
+	// The same settings as with -profile at startup.
+	profile, err := Cfg.Connection(name)
+	if err != nil {
+		ReportError(err)
+		return
+	}
+	var c *rpc.Client
+	var features rpc.Features
+	Async(func() (err error) {
+		if c, err = profile.NewClient(); err != nil {
+			return err
+		}
+		features, err = GetFeatures(c)
+		return err
+	}, func() {
+		Client.Assign(c)
+		Cfg.ProfileName = name
+		Features = features
+		SetTorrents(nil)
+		Stats = &SessionStats{}
+		Alt = &AltSpeed{}
+		SelectedIds = make(map[int]int)
+		CurrentCategory = ALL
+		CurrentStatus = CurrStatus{ALL, STATUS_ALL}
+		MainList.Clear()
+		InitMainList()
+		CategoryStatus.SetText(PrintCtgStat())
+		ShowStatusbar()
+		RequestResync()
+	})
+}
+
+func SortBy(order int) {
+	switch order {
+	case SORT_DATE:
+		sort.Slice(Torrents, func(i, j int) bool {
+			return Torrents[i].Date > Torrents[j].Date
+		})
+	case SORT_NAME:
+		sort.Slice(Torrents, func(i, j int) bool {
+			return strings.ToLower(Torrents[i].Name) < strings.ToLower(Torrents[j].Name)
+		})
+	case SORT_PROGRESS:
+		sort.Slice(Torrents, func(i, j int) bool {
+			return Torrents[i].Progress > Torrents[j].Progress
+		})
+	case SORT_SIZE:
+		sort.Slice(Torrents, func(i, j int) bool {
+			return Torrents[i].Size > Torrents[j].Size
+		})
+	case SORT_QUEUE:
+		if less := QueueLess(); !sort.SliceIsSorted(Torrents, less) {
+			sort.Slice(Torrents, less)
+		}
+	}
+}
+
+func QueueLess() func(i, j int) bool {
+	return func(i, j int) bool {
+		return Torrents[i].Queue < Torrents[j].Queue
+	}
+}
+
+// Sort order from its name in the config file.
+func ParseSortOrder(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "date":
+		return SORT_DATE, nil
+	case "name":
+		return SORT_NAME, nil
+	case "progress":
+		return SORT_PROGRESS, nil
+	case "size":
+		return SORT_SIZE, nil
+	case "queue":
+		return SORT_QUEUE, nil
+	}
+	return 0, errors.New(P("Unknown sort order") + ": " + s)
+}
+
+// Free space of a path asked from the daemon. The local filesystem is
+// checked instead only if the daemon runs on this machine.
 func DiskAvail(path string) string {
-	avText := ""
-	fs := unix.Statfs_t{}
-	if err := unix.Statfs(path, &fs); err == nil {
-		a := int64(fs.Bavail) * int64(fs.Bsize)
-		avText = fmt.Sprintf(" ([::u]%s "+P("Free")+"[::-])", FormatSize(a))
+	var a int64
+	source := P("daemon")
+	if fs, err := Client.FreeSpace(path); err == nil {
+		a = fs.Size
+	} else if Client.IsLocal() {
+		fs := unix.Statfs_t{}
+		if err := unix.Statfs(path, &fs); err != nil {
+			return ""
+		}
+		a = int64(fs.Bavail) * int64(fs.Bsize)
+		source = P("local")
+	} else {
+		return ""
+	}
+	return fmt.Sprintf(" ([::u]%s "+P("Free")+"[::-], %s)", FormatSize(a),
+		source)
+}
+
+// Add the free space to the directory items in the background.
+func ListDiskAvail(list *tview.List) {
+	dirs := make([]string, list.GetItemCount())
+	for i := range dirs {
+		_, dirs[i] = list.GetItemText(i)
 	}
-	return avText
+	go func() {
+		for i, d := range dirs {
+			i, d, avText := i, d, DiskAvail(d)
+			App.QueueUpdateDraw(func() {
+				if i >= list.GetItemCount() {
+					return
+				}
+				if _, s := list.GetItemText(i); s == d {
+					list.SetItemText(i, tview.Escape(d)+avText, d)
+				}
+			})
+		}
+	}()
+}
+
+// Show the path of the add dialog, its free space is added in the
+// background.
+func ShowSaveTo(dir string) {
+	SaveTo.SetText(P(" Path") + ": " + dir)
+	SaveToDir = dir
+	saveTo := SaveTo
+	go func() {
+		avText := DiskAvail(dir)
+		App.QueueUpdateDraw(func() {
+			if SaveTo == saveTo && SaveToDir == dir {
+				SaveTo.SetText(P(" Path") + avText + ": " + dir)
+			}
+		})
+	}()
 }
 
 // Input field when creating a new path/category.
@@ -672,7 +1002,7 @@ func ShowInputCtgPath(list *tview.List, tree *tview.TreeView, r int, mainKeys, m
 		s = P("Enter a new category name(s):")
 	} else { //DIRS
 		s = P("Enter a new path:")
-		t, _ = list.GetItemText(item)
+		_, t = list.GetItemText(item)
 	}
 	keys := []Key{{"Esc", P("Cancel")}}
 	inputField := NewInputFieldPrim(FormatKeys(keys) + s).SetText(t)
@@ -699,8 +1029,7 @@ func ShowInputCtgPath(list *tview.List, tree *tview.TreeView, r int, mainKeys, m
 				tLen := len(s)
 				if r == DIRS && tLen > 0 {
 					*dir = s
-					avText := DiskAvail(*dir)
-					SaveTo.SetText(P(" Path") + avText + ": " + s)
+					ShowSaveTo(s)
 				} else if tLen > 0 {
 					if s == DEFAULT {
 						*ctg = ""
@@ -723,18 +1052,10 @@ func AddDialogShowCtgDirs(r int, tree *tview.TreeView, ctg, dir *string, input f
 	PrintKeys(r)
 	list := NewListPrim()
 	if r == DIRS {
-		Dirs = make(map[string]int)
-		for _, t := range Torrents {
-			Dirs[strings.TrimSuffix(t.Path, "/")]++
-		}
-		d := make([]string, 0)
-		for k, _ := range Dirs {
-			d = append(d, k)
-		}
-		sort.Strings(d)
-		for _, k := range d {
+		for _, k := range Dirs {
 			list.AddItem(k, k, 0, nil)
 		}
+		ListDiskAvail(list)
 	} else {
 		InitCategory(CATEGORY, list)
 	}
@@ -763,8 +1084,7 @@ func AddDialogShowCtgDirs(r int, tree *tview.TreeView, ctg, dir *string, input f
 				_, s := list.GetItemText(item)
 				if r == DIRS {
 					*dir = s
-					avText := DiskAvail(*dir)
-					SaveTo.SetText(P(" Path") + avText + ": " + s)
+					ShowSaveTo(s)
 				} else {
 					if s == DEFAULT {
 						*ctg = ""
@@ -780,38 +1100,27 @@ func AddDialogShowCtgDirs(r int, tree *tview.TreeView, ctg, dir *string, input f
 }
 
 // Save/restore the last path/category from the file.
-func SetLast(r int, dir, ctg *string) {
+func SetLast(r int, dir, ctg *string) error {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	d := configDir + "/trango/"
 	if err := os.MkdirAll(d, 0774); err != nil {
-		log.Fatal(err)
+		return err
 	}
-	var f *os.File
-	if r == SAVE {
-		f, err = os.OpenFile(d+"last", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	} else {
-		f, err = os.OpenFile(d+"last", os.O_RDONLY|os.O_CREATE, 0644)
-	}
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
 	if r == SAVE {
 		c := *ctg
 		if *ctg == DEFAULT || *ctg == "" {
 			c = "Default"
 		}
-		fmt.Fprintf(f, "%s\n%s\n", *dir, c)
-		return
+		return WriteLast(d+"last", *dir, c)
 	}
+	f, err := os.OpenFile(d+"last", os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
 	sc := bufio.NewScanner(f)
 	i := 0
 	for ; sc.Scan(); i++ {
@@ -826,48 +1135,113 @@ func SetLast(r int, dir, ctg *string) {
 			}
 		}
 	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
 	if i != 1 {
 		if *dir == "" {
 			type SessionSettings struct {
 				DownloadDir string `json:"download-dir,omitempty"`
 			}
-			in := &Request{
-				Method: "session-get",
+			s := &SessionSettings{}
+			if err := Client.SessionGet(s); err != nil {
+				return err
 			}
-			out := &Response{Args: &SessionSettings{}}
-			GetRequest(in, out)
-			*dir = out.Args.(*SessionSettings).DownloadDir
+			*dir = s.DownloadDir
 		}
 		c := *ctg
 		if *ctg == "" {
 			c = "Default"
 			*ctg = DEFAULT
 		}
-		if err := f.Close(); err != nil {
-			log.Fatal(err)
+		return WriteLast(d+"last", *dir, c)
+	}
+	return nil
+}
+
+func WriteLast(filename, dir, ctg string) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%s\n%s\n", dir, ctg); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Fill in the path and the category of the add dialog which are not
+// given with the last ones. The path can be asked from the daemon.
+func LastDirCtg(dir, ctg *string) error {
+	if *dir == "" {
+		if err := SetLast(DIRS, dir, ctg); err != nil {
+			return err
 		}
-		f, err = os.OpenFile(d+"last", os.O_WRONLY|os.O_TRUNC, 0644)
-		fmt.Fprintf(f, "%s\n%s\n", *dir, c)
 	}
+	if *ctg == "" {
+		*ctg = DEFAULT
+		return SetLast(CATEGORY, dir, ctg)
+	}
+	return nil
 }
 
-func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
-	var rootDir []string
-	var rootLength int64
+// Show the files of a torrent to choose the wanted ones, its path and
+// category.
+func ShowAddDialog(info *metainfo.MetaInfo, files, ctg, dir *string, start, cancel *bool) {
+	var err error
+	if Features, err = GetFeatures(Client); err != nil {
+		Fatal(err)
+	}
+	if Stats, err = GetSessionStats(); err != nil {
+		Fatal(err)
+	}
+	if err = GetCtgDirs(); err != nil {
+		Fatal(err)
+	}
+	if err = LastDirCtg(dir, ctg); err != nil {
+		Fatal(err)
+	}
+	App = tview.NewApplication()
+	var saveErr error
+	NewAddDialog(info, files, ctg, dir, start,
+		func(c bool) {
+			*cancel = c
+			if !c {
+				saveErr = SetLast(SAVE, dir, ctg)
+			}
+			App.Stop()
+		})
+	App.SetRoot(MainGrid, true)
+	App.SetBeforeDrawFunc(func(s tcell.Screen) bool {
+		s.Clear()
+		return false
+	})
+	if err := App.Run(); err != nil {
+		panic(err)
+	}
+	if saveErr != nil {
+		fmt.Fprintln(os.Stderr, saveErr)
+	}
+}
+
+// Make the grid of the add dialog, it becomes MainGrid with its own Header
+// and Hotkeys. done is called when the dialog is closed by OK or cancelled.
+// The path and the category are not empty, see LastDirCtg().
+func NewAddDialog(info *metainfo.MetaInfo, files, ctg, dir *string, start *bool, done func(cancel bool)) *tview.Grid {
 	SelectedFileIds = make(map[string]*FileType)
-	rootDir, FilesAll, rootLength, _ = ParseTorrent(filename)
+	FilePriorities = make(map[int]int)
+	FilesAll = info.Files
 	nFiles := len(FilesAll)
-	TransmissionVersion = GetVersion()
-	GetSessionStats()
-	GetCtgDirs()
-	root := tview.NewTreeNode(rootDir[0])
+	rootDir := []string{info.Name}
+	root := tview.NewTreeNode(info.Name)
 	tree := tview.NewTreeView().
 		SetRoot(root).
 		SetCurrentNode(root)
 
 	length := TreeAdd(root, rootDir, true)
 	if nFiles == 0 {
-		length = rootLength
+		length = info.Length
 	}
 	root.Walk(SelectTreeItem)
 	root.SetText(fmt.Sprintf("%s (%s)", rootDir[0], FormatSize(length)))
@@ -890,15 +1264,8 @@ func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
 
 	Header = NewTextPrim(P("Add torrent")).SetTextAlign(tview.AlignCenter)
 	Header.SetBorder(true).SetBorderColor(tcell.ColorDefault)
-	if *dir == "" {
-		SetLast(DIRS, dir, ctg)
-	}
-	avText := DiskAvail(*dir)
-	SaveTo = NewTextPrim(P(" Path") + avText + ": " + *dir)
-	if *ctg == "" {
-		*ctg = DEFAULT
-		SetLast(CATEGORY, dir, ctg)
-	}
+	SaveTo = NewTextPrim("")
+	ShowSaveTo(*dir)
 	CategoryName = NewTextPrim(P(" Category: ") + *ctg)
 	startText := P(" Start torrent:") + " " + P("no")
 	if *start {
@@ -908,7 +1275,7 @@ func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
 	CurrentSize := NewTextPrim(P(" Size") + ": " + FormatSize(TotalSize))
 	keysText := []Key{{P("Space"), P("Get")}, {"Enter", P("(Un)expand dir")},
 		{"Esc", P("Cancel")}, {"F1", "OK"}, {"F2", P("Start yes/no")},
-		{"F3", P("Category")}, {"F4", P("Path")}}
+		{"F3", P("Category")}, {"F4", P("Path")}, {"+/-", P("Priority")}}
 	Hotkeys = NewTextPrim(FormatKeys(keysText))
 	MainGrid = tview.NewGrid().
 		SetRows(3, 1, 1, 1, 2, 0, 1).
@@ -923,20 +1290,14 @@ func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
 		AddItem(Hotkeys, 6, 0, 1, 5, 0, 0, false)
 
 	MainGrid.SetBackgroundColor(tcell.ColorDefault)
-	App = tview.NewApplication().SetRoot(MainGrid, true)
-	App.SetBeforeDrawFunc(func(s tcell.Screen) bool {
-		s.Clear()
-		return false
-	})
 	App.SetFocus(tree).
 		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 			switch event.Key() {
 			case tcell.KeyEsc:
-				*cancel = true
 				if *ctg == DEFAULT {
 					*ctg = ""
 				}
-				App.Stop()
+				done(true)
 			case tcell.KeyF1:
 				if *files != "" {
 					*files += ","
@@ -949,11 +1310,10 @@ func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
 				if nFiles == 0 {
 					*files = "0"
 				}
-				SetLast(SAVE, dir, ctg)
 				if *ctg == DEFAULT {
 					*ctg = ""
 				}
-				App.Stop()
+				done(false)
 			case tcell.KeyF2:
 				if *start {
 					*start = false
@@ -964,7 +1324,7 @@ func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
 				}
 			case tcell.KeyF3:
 				input := App.GetInputCapture()
-				if TransmissionVersion < 3 {
+				if !Features.Labels {
 					ShowVersionInfo(tree, CATEGORY, input)
 				} else {
 					AddDialogShowCtgDirs(CATEGORY, tree, ctg, dir, input)
@@ -984,14 +1344,15 @@ func ShowAddDialog(filename, files, ctg, dir *string, start, cancel *bool) {
 					node := tree.GetCurrentNode()
 					node.Walk(SelectTreeItem)
 					CurrentSize.SetText(P(" Size") + ": " + FormatSize(TotalSize))
+				case '+':
+					ChangePriority(tree.GetCurrentNode(), 1)
+				case '-':
+					ChangePriority(tree.GetCurrentNode(), -1)
 				}
 			}
 			return event
 		})
-
-	if err := App.Run(); err != nil {
-		panic(err)
-	}
+	return MainGrid
 }
 
 func TreeRestoreExpandSelected(node *tview.TreeNode, flag bool) {
@@ -1017,12 +1378,14 @@ func TreeAdd(target *tview.TreeNode, path []string, root bool) int64 {
 			p.Path = append(p.Path, path...)
 		}
 		p.Path = append(p.Path, file)
+		// Files of a new dir node got their priority from its parent.
+		mark := PriorityMark(FilePriorities[t.Id])
 		if t.Dir {
-			filesSrt[i].FName = fmt.Sprintf("[ ]%s", file)
+			filesSrt[i].FName = fmt.Sprintf("[ ]%s%s", mark, file)
 			p.Dir = true
 		} else {
-			filesSrt[i].FName = fmt.Sprintf("[ ]%s (%s)",
-				file, FormatSize(t.Length))
+			filesSrt[i].FName = fmt.Sprintf("[ ]%s%s (%s)",
+				mark, file, FormatSize(t.Length))
 			p.Length = t.Length
 		}
 		filesSrt[i].Name = file
@@ -1043,53 +1406,97 @@ func TreeAdd(target *tview.TreeNode, path []string, root bool) int64 {
 }
 
 // Read files from torrent (when adding).
-func ReadFiles(files []interface{}, path []string, root bool) (map[string]*FileType, int64) {
-	pathEq := func(p []interface{}, s []string) bool {
-		count := 0
-		max := len(p)
-		for i, t := range s {
-			if i < max && t == p[i] {
-				count++
-			}
+func ReadFiles(files []metainfo.File, path []string, root bool) (map[string]*FileType, int64) {
+	under := func(p []string) bool {
+		if len(p) <= len(path) {
+			return false
 		}
-		if count == len(s) {
-			return true
+		for i, s := range path {
+			if p[i] != s {
+				return false
+			}
 		}
-		return false
+		return true
 	}
 	var length int64
 	filenames := make(map[string]*FileType)
 	max := len(path)
-	index := 0
-	for _, v2 := range files {
-		le1 := v2.(map[string]interface{})["length"]
-		p := v2.(map[string]interface{})["path"].([]interface{})
-		for k3, v3 := range v2.(map[string]interface{}) {
-			if k3 == "path" {
-				f := v3.([]interface{})
-				if root {
-					filenames[p[0].(string)] = &FileType{Id: index}
-					filenames[p[0].(string)].Length = le1.(int64)
-					if len(f) > 1 {
-						filenames[p[0].(string)].Dir = true
-					}
-					length += le1.(int64)
-				} else if pathEq(f, path) {
-					filenames[p[max].(string)] = &FileType{Id: index}
-					filenames[p[max].(string)].Length = le1.(int64)
-					if len(f) > max+1 {
-						filenames[p[max].(string)].Dir = true
-					}
-					length += le1.(int64)
-				}
-			}
+	for index, f := range files {
+		p := f.Path
+		if root {
+			filenames[p[0]] = &FileType{Id: index, Length: f.Length,
+				Dir: len(p) > 1}
+		} else if under(p) {
+			filenames[p[max]] = &FileType{Id: index, Length: f.Length,
+				Dir: len(p) > max+1}
+		} else {
+			continue
 		}
-		index++
+		length += f.Length
 	}
-	// fmt.Println(FormatSize(length), path[max-1])
 	return filenames, length
 }
 
+// Raise or lower the priority of the file or of all files in the dir.
+func ChangePriority(node *tview.TreeNode, delta int) {
+	r := node.GetReference()
+	if r == nil {
+		return
+	}
+	ref := r.(Ref)
+	p := FilePriorities[ref.Id] + delta
+	if p > 1 || p < -1 {
+		return
+	}
+	ids := []int{ref.Id}
+	if ref.Dir {
+		ids = FileIdsUnder(ref.Path)
+	}
+	for _, id := range ids {
+		FilePriorities[id] = p
+	}
+	node.Walk(func(n, _ *tview.TreeNode) bool {
+		if n.GetReference() != nil {
+			text := n.GetText()
+			name := strings.TrimPrefix(text[3:], PriorityMark(1))
+			name = strings.TrimPrefix(name, PriorityMark(-1))
+			n.SetText(text[:3] + PriorityMark(p) + name)
+		}
+		return true
+	})
+}
+
+func PriorityMark(p int) string {
+	switch p {
+	case 1:
+		return "↑"
+	case -1:
+		return "↓"
+	}
+	return ""
+}
+
+// Indexes of the files in the dir of the add dialog.
+func FileIdsUnder(path []string) []int {
+	var ids []int
+	for i, f := range FilesAll {
+		if len(f.Path) <= len(path) {
+			continue
+		}
+		in := true
+		for j, s := range path {
+			if f.Path[j] != s {
+				in = false
+				break
+			}
+		}
+		if in {
+			ids = append(ids, i)
+		}
+	}
+	return ids
+}
+
 func SelectTreeItem(node, parent *tview.TreeNode) bool {
 	r := node.GetReference()
 	if r == nil {
@@ -1129,107 +1536,143 @@ func SelectTreeItem(node, parent *tview.TreeNode) bool {
 	return true
 }
 
-func ParseTorrent(filename *string) ([]string, []interface{}, int64, []string) {
-	file, err := os.Open(*filename)
-	if err != nil {
-		log.Fatal(err)
+// Settings of AddTorrent, read on the UI goroutine before it runs in the
+// background.
+type AddMode struct {
+	Labels   bool // The daemon takes the labels with torrent-add.
+	Metainfo bool // Send the contents of the local file, not its path.
+}
+
+func NewAddMode(filename string) AddMode {
+	return AddMode{
+		Labels: Features.AddLabels,
+		Metainfo: IsLocalFile(filename) && (SendMetainfo == METAINFO_ALWAYS ||
+			SendMetainfo == METAINFO_AUTO && !Client.IsLocal()),
 	}
-	defer file.Close()
+}
 
-	dict, err := bencode.Decode(file)
-	if err != nil {
-		log.Fatal(err)
+// Add the torrent with all its options in one torrent-add, only the labels
+// are set afterwards by a daemon older than 4.0. count is the number of
+// files, the files which are not in the files list are unwanted if it
+// is known. The options apply only to a new torrent, the result tells
+// whether it was a duplicate. The file priorities are taken from args.
+func AddTorrent(filename, ctg, files string, count int, mode AddMode, args *rpc.TorrentAddArgs) (*rpc.TorrentAddResult, error) {
+	var err error
+	var labels []string
+	if ctg != "" {
+		labels = strings.Split(ctg, ",")
+		if mode.Labels {
+			args.Labels = labels
+		}
 	}
-	var length int64
-	name := make([]string, 0)
-	trackers := make([]string, 0)
-	var files []interface{}
-	for key, value := range dict {
-		switch key {
-		case "info":
-			for k, v := range value.(map[string]interface{}) {
-				switch k {
-				case "name":
-					name = append(name, v.(string))
-				case "length":
-					length = v.(int64)
-				case "files":
-					files = v.([]interface{})
-				}
-			}
-		case "announce":
-			trackers = append(trackers, value.(string))
-		case "announce-list":
-			for _, v2 := range value.([]interface{}) {
-				trackers = append(trackers, v2.([]interface{})[0].(string))
-			}
+	if files != "" {
+		if args.FilesWanted, err = ParseFileIds(files); err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			args.FilesUnwanted = UnwantedFiles(args.FilesWanted, count)
 		}
 	}
-	return name, files, length, trackers
-}
-
-func AddTorrent(filename, dir, ctg, files string, paused bool) {
-	type arg struct {
-		Filename    string `json:"filename"`
-		Paused      bool   `json:"paused"`
-		DownloadDir string `json:"download-dir,omitempty"`
-		// Labels      []string `json:"labels"`
-	}
-	in := &Request{
-		Args: arg{
-			Filename:    filename,
-			Paused:      paused,
-			DownloadDir: dir,
-			// Labels:      []string{ctg},
-		},
-		Method: "torrent-add",
-	}
-	type tor struct {
-		Name       string `json:"name"`
-		Id         int    `json:"id"`
-		HashString string `json:"hashString"`
-	}
-	type rArg struct {
-		Duplicate tor `json:"torrent-duplicate,omitempty"`
-		Added     tor `json:"torrent-added,omitempty"`
-	}
-	out := &Response{Args: &rArg{}}
-	GetRequest(in, out)
-	res := out.Args.(*rArg).Duplicate
-	if res.HashString != "" {
-		fmt.Fprintln(os.Stderr, P("Torrent already added"))
-	} else {
-		res = out.Args.(*rArg).Added
+	switch {
+	case args.Metainfo != "":
+		// Downloaded from the URL already.
+	case mode.Metainfo:
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		args.Metainfo = base64.StdEncoding.EncodeToString(data)
+	default:
+		args.Filename = filename
 	}
-
-	if ctg != "" {
-		id := res.Id
-		labels := strings.Split(ctg, ",")
+	res, err := Client.TorrentAdd(args)
+	var re *rpc.ResultError
+	if errors.As(err, &re) {
+		return nil, errors.New(P("Torrent not added") + ": " + re.Result)
+	} else if err != nil {
+		return nil, err
+	}
+	if res.Duplicate != nil {
+		return res, nil
+	}
+	if res.Added == nil {
+		return nil, errors.New(P("Torrent not added"))
+	}
+	if labels != nil && !mode.Labels {
 		type arg struct {
 			Labels []string `json:"labels"`
 			Ids    []int    `json:"ids"`
 		}
-		in = &Request{
-			Args: arg{
-				Labels: labels,
-				Ids:    []int{id},
-			},
-			Method: "torrent-set",
+		return res, Client.TorrentSet(arg{Labels: labels,
+			Ids: []int{res.Added.Id}})
+	}
+	return res, nil
+}
+
+// The files of count which are not wanted.
+func UnwantedFiles(wanted []int, count int) []int {
+	want := make(map[int]bool, len(wanted))
+	for _, n := range wanted {
+		want[n] = true
+	}
+	unwanted := []int{}
+	for n := 0; n < count; n++ {
+		if !want[n] {
+			unwanted = append(unwanted, n)
 		}
-		out = &Response{}
-		GetRequest(in, out)
 	}
-	if files != "" {
-		id := res.Id
-		in = &Request{}
-		out = &Response{}
-		pdata := []byte(fmt.Sprintf(`{"method":"torrent-set","arguments":{"files-unwanted": [], "files-wanted": [ %s ],"ids":[%d]}}`, strings.TrimSuffix(files, ","), id))
-		GetRequest(in, out, pdata)
+	return unwanted
+}
+
+// Files with high and low priority chosen in the add dialog.
+func PriorityFiles() ([]int, []int) {
+	var high, low []int
+	for id, p := range FilePriorities {
+		if p > 0 {
+			high = append(high, id)
+		} else if p < 0 {
+			low = append(low, id)
+		}
 	}
+	sort.Ints(high)
+	sort.Ints(low)
+	return high, low
+}
+
+// Bandwidth priority from its name.
+func ParsePriority(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return -1, nil
+	case "", "normal":
+		return 0, nil
+	case "high":
+		return 1, nil
+	}
+	return 0, errors.New(P("Invalid value") + ": -priority " + s)
+}
+
+// Report whether the torrent is a file rather than a URL or a magnet link.
+func IsLocalFile(filename string) bool {
+	return !strings.HasPrefix(filename, "http") &&
+		!strings.HasPrefix(filename, "magnet")
+}
+
+// Parse the comma separated file indexes of -files.
+func ParseFileIds(files string) ([]int, error) {
+	var ids []int
+	for _, s := range strings.Split(strings.TrimSuffix(files, ","), ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, n)
+	}
+	return ids, nil
 }
 
 func ShowHelpInfo() {
-	MainMutex.Lock()
+	ViewOpen = true
 	MainGrid.RemoveItem(MainList)
 	keys := []Key{{"Esc", P("Close")}}
 	SetKeysHeaderText(P("Hotkeys"), FormatKeys(keys), tview.AlignCenter)
@@ -1246,7 +1689,17 @@ func ShowHelpInfo() {
 		" [red:]Ctrl+N[-:-]: " + P("create a new category for selected torrent(s)") + "\n" +
 		" [red:]Ctrl+U[-:-]: " + P("open comment url") + "\n" +
 		" [red:]Ctrl+O[-:-]: " + P("open download dir") + "\n" +
-		" [red:]Ctrl+L[-:-]: " + P("rename torrent") + "\n")
+		" [red:]Ctrl+L[-:-]: " + P("rename torrent") + "\n" +
+		" [red:]Ctrl+T[-:-]: " + P("switch daemon profile") + "\n" +
+		" [red:]Ctrl+E[-:-]: " + P("session settings") + "\n" +
+		" [red:]t[-:-]: " + P("toggle alternative speed") + "\n" +
+		" [red:]T[-:-]: " + P("alternative speed and schedule") + "\n" +
+		" [red:]o[-:-]: " + P("torrent limits and priority") + "\n" +
+		" [red:]s[-:-]: " + P("start now, skipping the queue") + "\n" +
+		" [red:]i[-:-]: " + P("session statistics") + "\n" +
+		" [red:]a[-:-]: " + P("add a torrent file, magnet link or URL") + "\n" +
+		" [red:]Alt+Up[-:-]/[red:]Alt+Down[-:-]: " + P("move up/down in the queue") + "\n" +
+		" [red:]Alt+Home[-:-]/[red:]Alt+End[-:-]: " + P("move to the top/bottom of the queue") + "\n")
 	hi := NewTextPrim(text)
 	MainGrid.AddItem(hi, 2, 0, 1, 3, 0, 0, true)
 	App.SetFocus(hi).
@@ -1255,14 +1708,14 @@ func ShowHelpInfo() {
 			case tcell.KeyEsc:
 				hi.Clear()
 				SwitchToMain(hi, LIST)
-				MainMutex.Unlock()
+				ViewOpen = false
 			}
 			return event
 		})
 }
 
 func ShowContentPreview() {
-	MainMutex.Lock()
+	ViewOpen = true
 	cpr := NewListPrim()
 	count := 0
 	for _, t := range Contents {
@@ -1287,7 +1740,7 @@ func ShowContentPreview() {
 	}
 	if count == 0 {
 		cpr.Clear()
-		MainMutex.Unlock()
+		ViewOpen = false
 		return
 	}
 	MainGrid.RemoveItem(MainList)
@@ -1304,7 +1757,7 @@ func ShowContentPreview() {
 				FilePath = ""
 				cpr.Clear()
 				SwitchToMain(cpr, LIST)
-				MainMutex.Unlock()
+				ViewOpen = false
 			case tcell.KeyEnter:
 				item := cpr.GetCurrentItem()
 				_, s := cpr.GetItemText(item)
@@ -1315,17 +1768,25 @@ func ShowContentPreview() {
 }
 
 func PreviewFile(id int) {
-	GetContentInfo(id)
-	n := len(Contents)
-	if n == 0 {
-		return
-	} else if n == 1 && int64(Contents[0].Progress) == Contents[0].Size {
-		OpenItem(FilePath + "/" + Contents[0].Name)
-		Contents = nil
-		FilePath = ""
-	} else if n > 1 {
-		ShowContentPreview()
-	}
+	var contents []Content
+	var path string
+	Async(func() (err error) {
+		contents, path, err = GetContentInfo(id)
+		return err
+	}, func() {
+		n := len(contents)
+		if n == 0 || App.GetFocus() != MainList {
+			return
+		}
+		Contents, FilePath = contents, path
+		if n == 1 && int64(Contents[0].Progress) == Contents[0].Size {
+			OpenItem(FilePath + "/" + Contents[0].Name)
+			Contents = nil
+			FilePath = ""
+		} else if n > 1 {
+			ShowContentPreview()
+		}
+	})
 }
 
 func OpenItem(s string) {
@@ -1339,72 +1800,38 @@ func OpenItem(s string) {
 func OpenAction(r string) {
 	item := MainList.GetCurrentItem()
 	id := GetId(item, MainList)
-	res := GetAction(id, r)
-	if len(res) > 0 {
-		OpenItem(res)
-	}
+	var res string
+	Async(func() (err error) {
+		res, err = GetAction(id, r)
+		return err
+	}, func() {
+		if len(res) > 0 {
+			OpenItem(res)
+		}
+	})
 }
 
-func TrackerAction(id, trackerId int, s, argName string) {
-	var q string
-	in := &Request{}
-	out := &Response{}
+func TrackerAction(id, trackerId int, s, argName string) error {
+	var q interface{}
 	if argName == "trackerReplace" {
-		q = (fmt.Sprintf("[%d, %q]", trackerId, s))
+		q = []interface{}{trackerId, s}
 	} else if argName == "trackerAdd" {
-		q = (fmt.Sprintf("[ %q ]", s))
+		q = []string{s}
 	} else { //trackerRemove
-		q = (fmt.Sprintf("[ %d ]", trackerId))
+		q = []int{trackerId}
 	}
-	pdata := []byte(fmt.Sprintf(`{"method":"torrent-set","arguments":{%q:%s,"ids":[%d]}}`, argName, q, id))
-	GetRequest(in, out, pdata)
+	return Client.TorrentSet(map[string]interface{}{
+		argName: q,
+		"ids":   []int{id},
+	})
 }
 
-func MovieTorrent(id int, dir string) {
-	var ids []int
-	for i, _ := range SelectedIds {
-		ids = append(ids, i)
-	}
-	if len(ids) == 0 {
-		ids = append(ids, id)
-	}
-	type arg struct {
-		Location string `json:"location"`
-		Move     bool   `json:"move"`
-		Ids      []int  `json:"ids"`
-	}
-	in := &Request{
-		Args: arg{
-			Location: dir,
-			Move:     true,
-			Ids:      ids,
-		},
-		Method: "torrent-set-location",
-	}
-	out := &Response{}
-	GetRequest(in, out)
+func MovieTorrent(ids []int, dir string) error {
+	return Client.TorrentSetLocation(ids, dir, true)
 }
 
-func RenameTorrent(id int, name, newName string) bool {
-	type arg struct {
-		Path string `json:"path"`
-		Name string `json:"name"`
-		Ids  []int  `json:"ids"`
-	}
-	in := &Request{
-		Args: arg{
-			Path: name,
-			Name: newName,
-			Ids:  []int{id},
-		},
-		Method: "torrent-rename-path",
-	}
-	out := &Response{}
-	GetRequest(in, out)
-	if out.Result == "success" {
-		return true
-	}
-	return false
+func RenameTorrent(id int, name, newName string) error {
+	return Client.TorrentRenamePath(id, name, newName)
 }
 
 func ShowConfirmation(s, method string, flag bool) {
@@ -1416,10 +1843,8 @@ func ShowConfirmation(s, method string, flag bool) {
 			case tcell.KeyEsc:
 				SwitchToMain(Hotkeys, KEYS)
 			case tcell.KeyEnter:
-				MainMutex.Lock()
 				TorAction(MainList.GetCurrentItem(), method, flag)
 				SelectedIds = make(map[int]int)
-				MainMutex.Unlock()
 				SwitchToMain(Hotkeys, KEYS)
 			}
 			return event
@@ -1427,7 +1852,7 @@ func ShowConfirmation(s, method string, flag bool) {
 }
 
 func ShowInputField(list *tview.List, r int, input func(event *tcell.EventKey) *tcell.EventKey) {
-	var s, t, dir string
+	var s, t string
 	var id, trackerId int
 	MainGrid.RemoveItem(Hotkeys)
 	item := list.GetCurrentItem()
@@ -1437,16 +1862,11 @@ func ShowInputField(list *tview.List, r int, input func(event *tcell.EventKey) *
 	case TORRENT_MOVE:
 		s = P("Move to:")
 		id = GetId(item, list)
-		dir = GetAction(id, "downloadDir")
-		t = dir
 	case TORRENT_RENAME:
 		s = P("Rename to:")
 		id = GetId(item, list)
-		for _, tor := range Torrents {
-			if tor.Id == id {
-				t = tor.Name
-				break
-			}
+		if tor, ok := TorrentById[id]; ok {
+			t = tor.Name
 		}
 	case TRACKER_ADD:
 		s = P("Enter announce URL:")
@@ -1466,13 +1886,20 @@ func ShowInputField(list *tview.List, r int, input func(event *tcell.EventKey) *
 	keys := []Key{{"Esc", P("Cancel")}}
 	inputField := NewInputFieldPrim(FormatKeys(keys) + s).SetText(t)
 	MainGrid.AddItem(inputField, 4, 0, 1, 3, 0, 0, false)
+	if r == TORRENT_MOVE {
+		var dir string
+		Async(func() (err error) {
+			dir, err = GetAction(id, "downloadDir")
+			return err
+		}, func() {
+			if inputField.GetText() == "" {
+				inputField.SetText(dir)
+			}
+		})
+	}
 	updateList := func() {
-		MainGrid.RemoveItem(inputField)
-		MainGrid.RemoveItem(list)
-		curItem := list.GetCurrentItem()
-		list.Clear()
-		MainGrid.AddItem(Hotkeys, 4, 0, 1, 3, 0, 0, false)
-		ShowTrackersInfo(list, curItem)
+		SetPrevInput(inputField, list, TRACKERS, input)
+		ShowTrackersInfo(list, list.GetCurrentItem())
 	}
 	App.SetFocus(inputField).
 		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -1489,7 +1916,6 @@ func ShowInputField(list *tview.List, r int, input func(event *tcell.EventKey) *
 				tLen := len(text)
 				switch r {
 				case CATEGORY:
-					MainMutex.Lock()
 					res := SetNewCategory(list.GetCurrentItem(), text)
 					if res {
 						CategoryFilter(ALL, OUT_GET_CURRENT)
@@ -1497,40 +1923,37 @@ func ShowInputField(list *tview.List, r int, input func(event *tcell.EventKey) *
 					} else {
 						SwitchToMain(inputField, KEYS)
 					}
-					MainMutex.Unlock()
 				case TORRENT_MOVE:
-					MovieTorrent(id, text)
+					ids := TargetIds(item)
+					Async(func() error {
+						return MovieTorrent(ids, text)
+					}, nil)
 					SwitchToMain(inputField, KEYS)
 				case TORRENT_RENAME:
-					MainMutex.Lock()
 					if tLen > 0 {
-						if RenameTorrent(id, t, text) {
-							var tDesc string
-							for _, tor := range Torrents {
-								if tor.Id == id {
-									i := strings.Index(tor.Desc, tor.Name)
-									tDesc = tor.Desc[:i]
-									tor.Name = text
-									break
-								}
+						Async(func() error {
+							return RenameTorrent(id, t, text)
+						}, func() {
+							if tor, ok := TorrentById[id]; ok {
+								tor.Name = text
+								tor.Desc = ""
 							}
-							list.SetItemText(item, tDesc+text, fmt.Sprintf("%d", id))
-						}
+						})
 					}
 					SwitchToMain(inputField, KEYS)
-					MainMutex.Unlock()
-				case TRACKER_ADD:
-					if tLen > 0 {
-						TrackerAction(id, trackerId,
-							text, "trackerAdd")
+				case TRACKER_ADD, TRACKER_RENAME:
+					if tLen == 0 {
+						updateList()
+						break
 					}
-					updateList()
-				case TRACKER_RENAME:
-					if tLen > 0 {
-						TrackerAction(id, trackerId,
-							text, "trackerReplace")
+					argName := "trackerAdd"
+					if r == TRACKER_RENAME {
+						argName = "trackerReplace"
 					}
-					updateList()
+					Async(func() error {
+						return TrackerAction(id, trackerId,
+							text, argName)
+					}, updateList)
 				}
 			}
 			return event
@@ -1555,12 +1978,9 @@ func SetNewCategory(item int, labels string) bool {
 	}
 	// ErrorLog(fmt.Sprintf("labels=%s\n", s))
 	for _, i := range ids {
-		for _, t := range Torrents {
-			if i == t.Id {
-				t.Labels = make([]string, 0)
-				t.Labels = append(t.Labels, s...)
-				break
-			}
+		if t, ok := TorrentById[i]; ok {
+			t.Labels = make([]string, 0)
+			t.Labels = append(t.Labels, s...)
 		}
 	}
 	var flag, res bool
@@ -1572,17 +1992,7 @@ func SetNewCategory(item int, labels string) bool {
 	}
 	if !flag {
 		max := MainList.GetItemCount()
-		var n, i int
-		for n, i = range ids {
-			for j := 0; j < max; j++ {
-				id := GetId(j, MainList)
-				if i == id {
-					MainList.RemoveItem(j)
-					break
-				}
-			}
-		}
-		if max == n+1 {
+		if RemoveRows(ids) == max {
 			// CategoryFilter(ALL)
 			res = true
 		}
@@ -1592,15 +2002,9 @@ func SetNewCategory(item int, labels string) bool {
 		Labels []string `json:"labels"`
 		Ids    []int    `json:"ids"`
 	}
-	in := &Request{
-		Args: arg{
-			Labels: s,
-			Ids:    ids,
-		},
-		Method: "torrent-set",
-	}
-	out := &Response{}
-	GetRequest(in, out)
+	Async(func() error {
+		return Client.TorrentSet(arg{Labels: s, Ids: ids})
+	}, nil)
 	return res
 }
 
@@ -1662,7 +2066,7 @@ func InitCategory(r int, ctgInfo *tview.List) {
 }
 
 func ShowCategoryInfo() {
-	MainMutex.Lock()
+	ViewOpen = true
 	MainGrid.RemoveItem(MainList)
 	keys := []Key{{"Esc", P("Close")},
 		{"F2", P("Set category for selected torrents")},
@@ -1674,7 +2078,7 @@ func ShowCategoryInfo() {
 		Status = make(map[string]int)
 		ctgInfo.Clear()
 		SwitchToMain(ctgInfo, LIST)
-		MainMutex.Unlock()
+		ViewOpen = false
 	}
 	MainGrid.AddItem(ctgInfo, 2, 0, 1, 3, 0, 0, true)
 	App.SetFocus(ctgInfo).
@@ -1709,12 +2113,9 @@ func CategoryFilter(ctg string, r int) {
 		CurrentStatus.Id = STATUS_ALL
 	}
 	MainList.Clear()
-	MainList = NewListPrim()
-	for _, t := range Torrents {
-		if CheckCategory(&ctg, &t.Labels) {
-			MainList.AddItem(t.Desc, fmt.Sprintf("%d", t.Id), 0, nil)
-		}
-	}
+	FillMainList(func(t *Torrent) bool {
+		return CheckCategory(&ctg, &t.Labels)
+	})
 	CategoryStatus.SetText(PrintCtgStat())
 }
 
@@ -1730,15 +2131,43 @@ func CheckCategory(currCtg *string, labels *[]string) bool {
 	return false
 }
 
-func ShowStatusInfo() {
-	MainMutex.Lock()
-	MainGrid.RemoveItem(MainList)
-	keys := []Key{{"Esc", P("Close")}}
-	SetKeysHeaderText(P("Status"), FormatKeys(keys), tview.AlignCenter)
-	statusInfo := NewListPrim()
+func ShowStatusInfo() {
+	ViewOpen = true
+	MainGrid.RemoveItem(MainList)
+	keys := []Key{{"Esc", P("Close")}}
+	SetKeysHeaderText(P("Status"), FormatKeys(keys), tview.AlignCenter)
+	statusInfo := NewListPrim()
+	var st []string
+	st, Status = CountStatus()
+	for _, k := range st {
+		statusInfo.AddItem(fmt.Sprintf("    %s (%d)",
+			k, Status[k]), fmt.Sprintf("%s", k), 0, nil)
+	}
+	MainGrid.AddItem(statusInfo, 2, 0, 1, 3, 0, 0, true)
+	App.SetFocus(statusInfo).
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEsc:
+				Status = make(map[string]int)
+				statusInfo.Clear()
+				SwitchToMain(statusInfo, LIST)
+				ViewOpen = false
+			case tcell.KeyEnter:
+				StatusFilter(statusInfo, OUT_GET_CURRENT)
+				Status = make(map[string]int)
+				statusInfo.Clear()
+				SwitchToMain(statusInfo, LIST)
+				ViewOpen = false
+			}
+			return event
+		})
+}
+
+// Names of the statuses and the number of torrents with each of them.
+func CountStatus() ([]string, map[string]int) {
 	st := []string{ALL, P("Downloading"), P("Queued"), P("Seeding"),
 		P("Stopped"), P("Active"), P("Errored")}
-	Status = map[string]int{
+	count := map[string]int{
 		st[0]: Stats.TorrentCount,
 		st[1]: 0,
 		st[2]: 0,
@@ -1749,53 +2178,29 @@ func ShowStatusInfo() {
 	}
 	for _, t := range Torrents {
 		if t.DlSpeed > 0 || t.UplSpeed > 0 {
-			Status[st[5]]++
+			count[st[5]]++
 		}
 		if t.Error != 0 {
-			Status[st[6]]++
+			count[st[6]]++
 		}
 		switch t.Status {
 		case STATUS_SEED:
-			Status[st[3]]++
+			count[st[3]]++
 		case STATUS_DOWNLOAD:
-			Status[st[1]]++
+			count[st[1]]++
 		case STATUS_DOWNLOAD_WAIT, STATUS_SEED_WAIT:
-			Status[st[2]]++
+			count[st[2]]++
 		}
 	}
-	for _, k := range st {
-		statusInfo.AddItem(fmt.Sprintf("    %s (%d)",
-			k, Status[k]), fmt.Sprintf("%s", k), 0, nil)
-	}
-	MainGrid.AddItem(statusInfo, 2, 0, 1, 3, 0, 0, true)
-	App.SetFocus(statusInfo).
-		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			switch event.Key() {
-			case tcell.KeyEsc:
-				Status = make(map[string]int)
-				statusInfo.Clear()
-				SwitchToMain(statusInfo, LIST)
-				MainMutex.Unlock()
-			case tcell.KeyEnter:
-				StatusFilter(statusInfo, OUT_GET_CURRENT)
-				Status = make(map[string]int)
-				statusInfo.Clear()
-				SwitchToMain(statusInfo, LIST)
-				MainMutex.Unlock()
-			}
-			return event
-		})
+	return st, count
 }
 
 func StatusFilter(statusInfo *tview.List, r int) {
 	setItems := func() {
 		MainList.Clear()
-		MainList = NewListPrim()
-		for _, t := range Torrents {
-			if CheckStatus(t.Status, t.DlSpeed, t.UplSpeed, t.Error) {
-				MainList.AddItem(t.Desc, fmt.Sprintf("%d", t.Id), 0, nil)
-			}
-		}
+		FillMainList(func(t *Torrent) bool {
+			return CheckStatus(t.Status, t.DlSpeed, t.UplSpeed, t.Error)
+		})
 		CategoryStatus.SetText(PrintCtgStat())
 	}
 
@@ -1845,7 +2250,8 @@ func CheckStatus(status, dl, upl, error int) bool {
 	return false
 }
 
-func TorAction(item int, method string, flag bool) {
+// Selected torrents or the torrent of the item.
+func TargetIds(item int) []int {
 	var ids []int
 	for id, _ := range SelectedIds {
 		ids = append(ids, id)
@@ -1854,36 +2260,32 @@ func TorAction(item int, method string, flag bool) {
 		id := GetId(item, MainList)
 		ids = append(ids, id)
 	}
-	in := &Request{}
-	if method == "torrent-remove" {
-		type arg struct {
-			DeleteData bool  `json:"delete-local-data"`
-			Ids        []int `json:"ids"`
-		}
-		in = &Request{
-			Args: arg{
-				DeleteData: flag,
-				Ids:        ids,
-			},
-			Method: method,
-		}
-	} else {
-		type arg struct {
-			Ids []int `json:"ids"`
-		}
-		in = &Request{
-			Args: arg{
-				Ids: ids,
-			},
-			Method: method,
-		}
+	return ids
+}
+
+// Move the torrents in the queue with one of queue-move-top, queue-move-up,
+// queue-move-down and queue-move-bottom.
+func QueueMove(item int, method string) {
+	if MainList.GetItemCount() == 0 {
+		return
 	}
-	out := &Response{}
-	GetRequest(in, out)
+	ids := TargetIds(item)
+	Async(func() error {
+		return Client.TorrentAction(method, ids)
+	}, RequestResync) // The other torrents move too.
+}
+
+func TorAction(item int, method string, flag bool) {
+	ids := TargetIds(item)
+	Async(func() error {
+		if method == "torrent-remove" {
+			return Client.TorrentRemove(ids, flag)
+		}
+		return Client.TorrentAction(method, ids)
+	}, RequestRefresh)
 }
 
 func SelectAll(list *tview.List, sel bool) {
-	MainMutex.Lock()
 	max := list.GetItemCount()
 	for i := 0; i < max; i++ {
 		m, s := list.GetItemText(i)
@@ -1901,7 +2303,6 @@ func SelectAll(list *tview.List, sel bool) {
 			}
 		}
 	}
-	MainMutex.Unlock()
 }
 
 func SelectItem(list *tview.List) {
@@ -1920,8 +2321,7 @@ func SelectItem(list *tview.List) {
 	}
 }
 
-func TrackersAdd(id int) *tview.List {
-	ti := GetTrackersInfo(id)
+func TrackersAdd(ti []TrackersInfo) *tview.List {
 	n := len(ti)
 	if n == 0 {
 		return nil
@@ -1957,24 +2357,41 @@ func TrackersAdd(id int) *tview.List {
 	return trackersInfo
 }
 
+// Show the trackers in place of the list once they are received.
 func ShowTrackersInfo(list *tview.List, curItem int) {
 	item := MainList.GetCurrentItem()
 	id := GetId(item, MainList)
-	trackersInfo := TrackersAdd(id)
-	if trackersInfo == nil {
-		MainMutex.Unlock()
-		return
-	}
-	MainGrid.RemoveItem(list)
-	MainGrid.AddItem(trackersInfo, 2, 0, 1, 3, 0, 0, true)
-	trackersInfo.SetCurrentItem(curItem)
+	var ti []TrackersInfo
+	Async(func() (err error) {
+		ti, err = GetTrackersInfo(id)
+		return err
+	}, func() {
+		if App.GetFocus() != list {
+			return // Another view was opened meanwhile.
+		}
+		trackersInfo := TrackersAdd(ti)
+		if trackersInfo == nil {
+			return
+		}
+		ViewOpen = true
+		MainGrid.RemoveItem(list)
+		if list != MainList {
+			list.Clear()
+		}
+		MainGrid.AddItem(trackersInfo, 2, 0, 1, 3, 0, 0, true)
+		trackersInfo.SetCurrentItem(curItem)
+		SetTrackersInput(trackersInfo, id)
+	})
+}
+
+func SetTrackersInput(trackersInfo *tview.List, id int) {
 	App.SetFocus(trackersInfo).
 		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 			switch event.Key() {
 			case tcell.KeyEsc:
 				trackersInfo.Clear()
 				SwitchToMain(trackersInfo, LIST)
-				MainMutex.Unlock()
+				ViewOpen = false
 			case tcell.KeyF2:
 				ShowInputField(trackersInfo, TRACKER_RENAME,
 					App.GetInputCapture())
@@ -1984,8 +2401,12 @@ func ShowTrackersInfo(list *tview.List, curItem int) {
 			case tcell.KeyDelete:
 				tItem := trackersInfo.GetCurrentItem()
 				trackerId := GetId(tItem, trackersInfo)
-				TrackerAction(id, trackerId, "", "trackerRemove")
-				trackersInfo.RemoveItem(tItem)
+				Async(func() error {
+					return TrackerAction(id, trackerId, "",
+						"trackerRemove")
+				}, func() {
+					trackersInfo.RemoveItem(tItem)
+				})
 			}
 			return event
 		})
@@ -2019,11 +2440,23 @@ func PrintKeys(r int) {
 }
 
 func ShowContentInfo(item int) {
-	MainMutex.Lock()
-	GetContentInfo(GetId(item, MainList))
-	if len(Contents) == 0 {
-		return
-	}
+	var contents []Content
+	var path string
+	id := GetId(item, MainList)
+	Async(func() (err error) {
+		contents, path, err = GetContentInfo(id)
+		return err
+	}, func() {
+		if len(contents) == 0 || App.GetFocus() != MainList {
+			return
+		}
+		Contents, FilePath = contents, path
+		ShowContent(item)
+	})
+}
+
+func ShowContent(item int) {
+	ViewOpen = true
 	MakeContentTree()
 	MainGrid.RemoveItem(MainList)
 	PrintKeys(CONTENT)
@@ -2066,7 +2499,7 @@ func ShowContentInfo(item int) {
 				FilePath = ""
 				contentInfo.Clear()
 				SwitchToMain(contentInfo, LIST)
-				MainMutex.Unlock()
+				ViewOpen = false
 			case tcell.KeyEnter:
 				it := contentInfo.GetCurrentItem()
 				if ContentsTree[it].Progress == 1 {
@@ -2203,12 +2636,7 @@ func ContentPriorityAction(mainItem, count int, contentInfo *tview.List, key boo
 
 func ContentRpc(item, pnum, r int, fileIds []int, wanted bool) {
 	var q string
-	in := &Request{}
-	out := &Response{}
 	id := GetId(item, MainList)
-	s1 := fmt.Sprintf("%d", fileIds)
-	s2 := strings.Split(s1, " ")
-	strFileIds := strings.Join(s2, ",")
 	if r == WANTED_SET {
 		if wanted {
 			q = "files-wanted"
@@ -2225,8 +2653,12 @@ func ContentRpc(item, pnum, r int, fileIds []int, wanted bool) {
 			q = "priority-normal"
 		}
 	}
-	pdata := []byte(fmt.Sprintf(`{"method":"torrent-set","arguments":{%q:%s,"ids":[%d]}}`, q, strFileIds, id))
-	GetRequest(in, out, pdata)
+	Async(func() error {
+		return Client.TorrentSet(map[string]interface{}{
+			q:     fileIds,
+			"ids": []int{id},
+		})
+	}, nil)
 }
 
 func ContentWantedAction(mainItem, count int, contentInfo *tview.List) {
@@ -2528,16 +2960,33 @@ func ComparePath(i int, buf *string) bool {
 	return false
 }
 
-func PrintPeers(quit <-chan bool, id int) {
+func PrintPeers(quit <-chan bool, pause *int32, id int) {
 	for {
 		select {
 		case <-quit:
 			return
-		default:
-			PeersMutex.Lock()
-			GetSessionStats()
+		case <-time.After(UpdateInt * time.Second):
+		}
+		if atomic.LoadInt32(pause) != 0 {
+			continue
+		}
+		pi, err := GetPeersInfo(id)
+		var stats *SessionStats
+		if err == nil {
+			stats, err = GetSessionStats()
+		}
+		App.QueueUpdateDraw(func() {
+			select {
+			case <-quit:
+				return
+			default:
+			}
+			if err != nil {
+				ReportError(err)
+				return
+			}
+			Stats = stats
 			Peers.Clear()
-			pi := GetPeersInfo(id)
 			for _, p := range pi {
 				fmt.Fprintf(Peers,
 					" %20s  %6s    %10s   %10s  %10s"+
@@ -2549,15 +2998,12 @@ func PrintPeers(quit <-chan bool, id int) {
 					p.FlagStr, p.ClientName)
 			}
 			ShowStatusbar()
-			App.Draw()
-			PeersMutex.Unlock()
-			time.Sleep(UpdateInt * time.Second)
-		}
+		})
 	}
 }
 
 func ShowPeersInfo(item int) {
-	MainMutex.Lock()
+	ViewOpen = true
 	MainGrid.RemoveItem(MainList)
 	title := fmt.Sprintf("%*s %s", 18, "IP",
 		P(" |  Done  | Downloading | Uploading |   Flags   | Client"))
@@ -2565,26 +3011,20 @@ func ShowPeersInfo(item int) {
 	SetKeysHeaderText(title, FormatKeys(keys), tview.AlignLeft)
 	Peers = NewTextPrim(" ").SetWrap(false)
 	MainGrid.AddItem(Peers, 2, 0, 1, 3, 0, 0, true)
-	var pause bool
+	var pause int32
 	quit := make(chan bool)
 	id := GetId(item, MainList)
-	go PrintPeers(quit, id)
+	go PrintPeers(quit, &pause, id)
 	App.SetFocus(Peers).
 		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 			switch event.Key() {
 			case tcell.KeyEsc:
-				quit <- true
+				close(quit)
 				Peers.Clear()
 				SwitchToMain(Peers, LIST)
-				MainMutex.Unlock()
+				ViewOpen = false
 			case tcell.KeyF2:
-				if !pause {
-					PeersMutex.Lock()
-					pause = true
-				} else {
-					PeersMutex.Unlock()
-					pause = false
-				}
+				atomic.StoreInt32(&pause, 1-atomic.LoadInt32(&pause))
 			}
 			return event
 		})
@@ -2595,7 +3035,15 @@ func SearchItem(s string, i int, list *tview.List) (int, int) {
 	s = strings.ToLower(s)
 	max := list.GetItemCount()
 	for ; i < max; i++ {
-		res, _ = list.GetItemText(i)
+		if list == MainList && i < len(Rows) {
+			// Only the rows near the screen have their text.
+			res = ""
+			if t := TorrentById[Rows[i]]; t != nil {
+				res = t.Name
+			}
+		} else {
+			res, _ = list.GetItemText(i)
+		}
 		res = strings.ToLower(res)
 		if strings.Contains(res, s) {
 			list.SetCurrentItem(i)
@@ -2672,8 +3120,20 @@ func GetId(item int, list *tview.List) int {
 }
 
 func ShowGeneralInfo(item int) {
-	MainMutex.Lock()
-	gi := GetGeneralInfo(GetId(item, MainList))
+	var gi []*GeneralInfo
+	id := GetId(item, MainList)
+	Async(func() (err error) {
+		gi, err = GetGeneralInfo(id)
+		return err
+	}, func() {
+		if len(gi) > 0 && App.GetFocus() == MainList {
+			ShowGeneral(gi[0])
+		}
+	})
+}
+
+func ShowGeneral(gi *GeneralInfo) {
+	ViewOpen = true
 	MainGrid.RemoveItem(MainList)
 	keys := []Key{{"Esc", P("Close")}}
 	SetKeysHeaderText(P("General Info"), FormatKeys(keys), tview.AlignCenter)
@@ -2692,17 +3152,17 @@ func ShowGeneralInfo(item int) {
 		"%*s: %s\n"+"%*s: %s\n"+"%*s: %s\n\n"+"%*s: %s\n"+
 		"%*s: %g\n"+"%*s: %s\n"+"%*s: %s\n"+"%*s: %s\n"+
 		"%*s: %s\n"+"%*s: %s\n",
-		n, pre[0], gi[0].Name, n, pre[1], gi[0].Id,
-		n, pre[2], gi[0].HashString,
-		n, pre[3], strings.Join(gi[0].Labels, ","),
-		n, pre[4], gi[0].DownloadDir, n, pre[5], gi[0].Comment,
-		n, pre[6], FormatSize(gi[0].UploadedEver),
-		n, pre[7], FormatRatio(gi[0].UploadRatio),
-		n, pre[8], FormatDate(gi[0].DateCreated),
-		n, pre[9], gi[0].Creator,
-		n, pre[10], FormatDate(gi[0].AddedDate),
-		n, pre[11], FormatSize(gi[0].TotalSize),
-		n, pre[12], gi[0].ErrorString)
+		n, pre[0], gi.Name, n, pre[1], gi.Id,
+		n, pre[2], gi.HashString,
+		n, pre[3], strings.Join(gi.Labels, ","),
+		n, pre[4], gi.DownloadDir, n, pre[5], gi.Comment,
+		n, pre[6], FormatSize(gi.UploadedEver),
+		n, pre[7], FormatRatio(gi.UploadRatio),
+		n, pre[8], FormatDate(gi.DateCreated),
+		n, pre[9], gi.Creator,
+		n, pre[10], FormatDate(gi.AddedDate),
+		n, pre[11], FormatSize(gi.TotalSize),
+		n, pre[12], gi.ErrorString)
 	genInfo := NewTextPrim(text)
 	MainGrid.AddItem(genInfo, 2, 0, 1, 3, 0, 0, true)
 	App.SetFocus(genInfo).
@@ -2711,34 +3171,210 @@ func ShowGeneralInfo(item int) {
 			case tcell.KeyEsc:
 				genInfo.Clear()
 				SwitchToMain(genInfo, LIST)
-				MainMutex.Unlock()
+				ViewOpen = false
 			}
 			return event
 		})
 }
 
+// Torrents received by Refresh().
+type RefreshResult struct {
+	Stats    *SessionStats
+	Alt      *AltSpeed  // Only with a full reload
+	Torrents []*Torrent // All torrents after a full reload
+	// Recently changed torrents and their stats otherwise.
+	Changed []*Torrent
+	Info    []*TorrentInfo
+	Removed []int
+}
+
+// Poll the daemon in the background, the updates are applied in the UI
+// goroutine. Only recently active torrents are fetched, all of them are
+// reloaded every ResyncCycles updates and when Resync is set. A value sent
+// to RefreshNow skips the wait.
 func ShowCurrent() {
-	var prev int
-	for {
-		MainMutex.Lock()
-		prev = Stats.TorrentCount
-		GetSessionStats()
-		GetTorrentsInfo()
-		if Stats.TorrentCount != prev {
-			GetTorrents()
+	for cycle := 1; ; cycle++ {
+		full := atomic.SwapInt32(&Resync, 0) != 0 ||
+			ResyncCycles > 0 && cycle%ResyncCycles == 0
+		gen := Client.Generation()
+		r, err := Refresh(full)
+		if rpc.IsConnError(err) {
+			Reconnect()
+			continue
+		}
+		App.QueueUpdateDraw(func() {
+			if gen != Client.Generation() {
+				return // The profile was switched meanwhile.
+			}
+			if err != nil {
+				ReportError(err)
+				return
+			}
+			ApplyRefresh(r)
+		})
+		select {
+		case <-RefreshNow:
+		case <-time.After(UpdateInt * time.Second):
+		}
+	}
+}
+
+// Make ShowCurrent() update the torrents without waiting.
+func RequestRefresh() {
+	select {
+	case RefreshNow <- true:
+	default:
+	}
+}
+
+// Reload all torrents on the next update.
+func RequestResync() {
+	atomic.StoreInt32(&Resync, 1)
+	RequestRefresh()
+}
+
+// Get all torrents or only the recently changed ones.
+func Refresh(full bool) (*RefreshResult, error) {
+	stats, err := GetSessionStats()
+	if err != nil {
+		return nil, err
+	}
+	r := &RefreshResult{Stats: stats}
+	if full {
+		// The alt speed changes only by its schedule between the saves.
+		if r.Alt, err = GetAltSpeed(); err != nil {
+			return nil, err
+		}
+		r.Torrents, err = GetTorrents()
+	} else {
+		r.Changed, r.Info, r.Removed, err = GetRecentTorrents()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Update the torrents with the refresh result. The list is not redrawn
+// while another view replaces it.
+func ApplyRefresh(r *RefreshResult) {
+	Stats = r.Stats
+	if r.Alt != nil {
+		Alt = r.Alt
+	}
+	var added bool
+	if r.Torrents != nil {
+		added = !SameTorrents(r.Torrents)
+		SetTorrents(r.Torrents)
+	} else {
+		added = MergeTorrents(r.Changed, r.Info, r.Removed)
+	}
+	if added || SortOrder == SORT_QUEUE {
+		// The queue order changes without adding torrents.
+		SortBy(SortOrder)
+	}
+	// The rows are rebuilt when the list is in front again if an input
+	// field or a prompt is open.
+	if !ViewOpen && App.GetFocus() == MainList {
+		if added {
 			UpdateNewTorrents()
 		}
 		UpdateCurrentTorrents()
-		ShowStatusbar()
-		App.Draw()
-		MainMutex.Unlock()
-		time.Sleep(UpdateInt * time.Second)
+		ShowPendingAdd()
+	}
+	ShowStatusbar()
+	if r.Torrents == nil && len(Torrents) != Stats.TorrentCount {
+		// Some change was missed, e.g. during a long pause.
+		RequestResync()
+	}
+}
+
+// Report whether torrents has the ids of Torrents.
+func SameTorrents(torrents []*Torrent) bool {
+	if len(torrents) != len(TorrentById) {
+		return false
+	}
+	for _, t := range torrents {
+		if _, ok := TorrentById[t.Id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge the changed torrents into Torrents and drop the removed ones.
+// Report whether torrents were added or removed.
+func MergeTorrents(changed []*Torrent, info []*TorrentInfo, removed []int) bool {
+	var res bool
+	for i, c := range changed {
+		t, ok := TorrentById[c.Id]
+		if !ok {
+			Torrents = append(Torrents, c)
+			TorrentById[c.Id] = c
+			t = c
+			res = true
+		}
+		t.Name, t.Labels = c.Name, c.Labels
+		SetTorrentInfo(t, info[i])
+	}
+	if len(removed) > 0 {
+		drop := make(map[int]bool, len(removed))
+		for _, id := range removed {
+			drop[id] = true
+			delete(SelectedIds, id)
+			delete(TorrentById, id)
+		}
+		n := 0
+		for _, t := range Torrents {
+			if !drop[t.Id] {
+				Torrents[n] = t
+				n++
+			}
+		}
+		res = res || n != len(Torrents)
+		Torrents = Torrents[:n]
+	}
+	return res
+}
+
+// Wait for the daemon with exponential backoff, then renew the session
+// and reload the torrents keeping the current filter and selection.
+func Reconnect() {
+	delay := time.Second
+	for {
+		for n := int(delay / time.Second); n > 0; n-- {
+			text := "[red:]" + P("Disconnected, retrying in %ds", n) + "[-:]"
+			App.QueueUpdateDraw(func() {
+				Statusbar.SetText(text)
+			})
+			time.Sleep(time.Second)
+		}
+		gen := Client.Generation()
+		Client.ResetSession()
+		r, err := Refresh(true)
+		if err == nil {
+			App.QueueUpdateDraw(func() {
+				if gen == Client.Generation() {
+					ApplyRefresh(r)
+				}
+			})
+			return
+		}
+		if !rpc.IsConnError(err) {
+			App.QueueUpdateDraw(func() {
+				ReportError(err)
+			})
+			RequestResync()
+			return
+		}
+		if delay < MAX_RETRY_DELAY {
+			delay *= 2
+		}
 	}
 }
 
 func UpdateNewTorrents() {
 	item := MainList.GetCurrentItem()
-	MainGrid.RemoveItem(App.GetFocus())
 	MainGrid.RemoveItem(MainList)
 	MainList.Clear()
 	InitMainList()
@@ -2749,23 +3385,24 @@ func UpdateNewTorrents() {
 	SwitchToMain(Hotkeys, ALL_T)
 }
 
+// Match the rows to the filtered torrents. The text of the rows is
+// updated by RenderRows() when they are drawn.
 func UpdateCurrentTorrents() {
-	var cfmt string
 	n := MainList.GetItemCount()
 	i := 0
 	for _, t := range Torrents {
-		if CheckStatCtg(t.Status, t.DlSpeed, t.UplSpeed, t.Error, &t.Labels) {
-			if _, ok := SelectedIds[t.Id]; ok {
-				cfmt = "[black:yellow]%s"
-			} else {
-				cfmt = "%s"
-			}
-			if i < n {
-				MainList.SetItemText(i,
-					fmt.Sprintf(cfmt, t.Desc), fmt.Sprintf("%d", t.Id))
-				i++
+		if !CheckStatCtg(t.Status, t.DlSpeed, t.UplSpeed, t.Error, &t.Labels) {
+			continue
+		}
+		if i < n && Rows[i] != t.Id {
+			if RowById[Rows[i]] == i {
+				delete(RowById, Rows[i])
 			}
+			Rows[i] = t.Id
+			RowById[t.Id] = i
+			MainList.SetItemText(i, "", strconv.Itoa(t.Id))
 		}
+		i++
 	}
 
 	if n != i {
@@ -2811,13 +3448,96 @@ func ShowStatusbar() {
 		P("Uploading")+": %s ",
 		Stats.TorrentCount, Stats.ActiveTorrentCount,
 		Stats.PausedTorrentCount, FormatSpeed(Stats.DownloadSpeed),
-		FormatSpeed(Stats.UploadSpeed)))
+		FormatSpeed(Stats.UploadSpeed)) + FormatAltSpeed(Alt))
 }
 
 func InitMainList() {
+	FillMainList(nil)
+}
+
+// Create MainList with the torrents accepted by filter, or all of them.
+// The rows are empty until RenderRows() formats them.
+func FillMainList(filter func(t *Torrent) bool) {
 	MainList = NewListPrim()
+	Rows = Rows[:0]
+	RowById = make(map[int]int)
 	for _, t := range Torrents {
-		MainList.AddItem(t.Desc, fmt.Sprintf("%d", t.Id), 0, nil)
+		if filter == nil || filter(t) {
+			RowById[t.Id] = len(Rows)
+			Rows = append(Rows, t.Id)
+			MainList.AddItem("", strconv.Itoa(t.Id), 0, nil)
+		}
+	}
+}
+
+// Remove the rows of the torrents from MainList, return how many were found.
+func RemoveRows(ids []int) int {
+	var rows []int
+	for _, id := range ids {
+		if row, ok := RowById[id]; ok {
+			rows = append(rows, row)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rows)))
+	for _, row := range rows {
+		MainList.RemoveItem(row)
+		delete(RowById, Rows[row])
+		Rows = append(Rows[:row], Rows[row+1:]...)
+	}
+	if len(rows) > 0 {
+		for row := rows[len(rows)-1]; row < len(Rows); row++ {
+			RowById[Rows[row]] = row
+		}
+	}
+	return len(rows)
+}
+
+func RowText(t *Torrent) string {
+	if _, ok := SelectedIds[t.Id]; ok {
+		return "[black:yellow]" + TorrentDesc(t)
+	}
+	return TorrentDesc(t)
+}
+
+// Update the text of the MainList rows which can be drawn next, i.e. the
+// visible ones and those around the current item.
+func RenderRows() {
+	_, _, _, height := MainList.GetInnerRect()
+	cur := MainList.GetCurrentItem()
+	from, _ := MainList.GetOffset()
+	to := from + height
+	if cur-height < from {
+		from = cur - height
+	}
+	if cur+height > to {
+		to = cur + height
+	}
+	if from < 0 {
+		from = 0
+	}
+	if n := MainList.GetItemCount(); to > n {
+		to = n
+	}
+	if to > len(Rows) {
+		to = len(Rows)
+	}
+	for row := from; row < to; row++ {
+		t, ok := TorrentById[Rows[row]]
+		if !ok {
+			continue
+		}
+		text := RowText(t)
+		if main, _ := MainList.GetItemText(row); main != text {
+			MainList.SetItemText(row, text, strconv.Itoa(t.Id))
+		}
+	}
+}
+
+func SetTorrents(torrents []*Torrent) {
+	Torrents = torrents
+	TorrentById = make(map[int]*Torrent, len(torrents))
+	for _, t := range torrents {
+		TorrentById[t.Id] = t
 	}
 }
 
@@ -2897,6 +3617,15 @@ func FormatProgress(pr float64) string {
 	return fmt.Sprintf("%1.*g%%", n, pr)
 }
 
+// Queue position of a waiting torrent, counted from 1.
+func FormatQueue(t *Torrent) string {
+	if t.Error == 0 && (t.Status == STATUS_DOWNLOAD_WAIT ||
+		t.Status == STATUS_SEED_WAIT) {
+		return strconv.Itoa(t.Queue + 1)
+	}
+	return ""
+}
+
 func FormatStatus(status, err int) string {
 	if err != 0 {
 		return StatSymb.Errored
@@ -2972,67 +3701,76 @@ func FormatEta(seconds int64) string {
 	}
 }
 
-func GetVersion() int {
-	type SessionSettings struct {
-		Version string `json:"version,omitempty"`
+// Get the features of the daemon and make the client use them.
+func GetFeatures(c *rpc.Client) (rpc.Features, error) {
+	v, err := c.SessionVersion()
+	if err != nil {
+		return rpc.Features{}, err
 	}
-	in := &Request{
-		Method: "session-get",
+	f := v.Features()
+	c.UseTableFormat(f.TableFormat)
+	return f, nil
+}
+
+func GetCtgDirs() error {
+	out := &TorrentsGet{}
+	err := Client.TorrentGet(nil,
+		[]string{"id", "labels", "downloadDir"}, out)
+	if err != nil {
+		return err
 	}
-	out := &Response{Args: &SessionSettings{}}
-	GetRequest(in, out)
-	ver := out.Args.(*SessionSettings).Version
-	v, err := strconv.Atoi(ver[:1])
+	Torrents = out.All
+	Dirs = TorrentDirs(out.All)
+	return nil
+}
+
+// Get the download dirs of all torrents.
+func GetDownloadDirs() ([]string, error) {
+	out := &TorrentsGet{}
+	err := Client.TorrentGet(nil, []string{"downloadDir"}, out)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	return v
+	return TorrentDirs(out.All), nil
 }
 
-func GetCtgDirs() {
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"id", "labels", "downloadDir"},
-		},
-		Method: "torrent-get",
+// Sorted download dirs of the torrents without duplicates.
+func TorrentDirs(torrents []*Torrent) []string {
+	seen := make(map[string]bool)
+	dirs := make([]string, 0)
+	for _, t := range torrents {
+		d := strings.TrimSuffix(t.Path, "/")
+		if !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
 	}
-	out := &Response{Args: &TorrentsGet{}}
-	GetRequest(in, out)
-	Torrents = out.Args.(*TorrentsGet).All
+	sort.Strings(dirs)
+	return dirs
 }
 
-func GetAction(id int, r string) string {
-	in := &Request{
-		Args: Arg{
-			Fields: []string{r},
-			Ids:    []int{id},
-		},
-		Method: "torrent-get",
+func GetAction(id int, r string) (string, error) {
+	type Action struct {
+		Url string `json:"comment"`
+		Dir string `json:"downloadDir"`
+	}
+	type Get struct {
+		All []Action `json:"torrents"`
+	}
+	out := &Get{}
+	if err := Client.TorrentGet([]int{id}, []string{r}, out); err != nil {
+		return "", err
+	}
+	if len(out.All) == 0 {
+		return "", nil
 	}
 	if r == "comment" {
-		type Comment struct {
-			Url string `json:"comment"`
-		}
-		type Get struct {
-			All []Comment `json:"torrents"`
-		}
-		out := &Response{Args: &Get{}}
-		GetRequest(in, out)
-		return out.Args.(*Get).All[0].Url
-	} else {
-		type Dir struct {
-			Dir string `json:"downloadDir"`
-		}
-		type Get struct {
-			All []Dir `json:"torrents"`
-		}
-		out := &Response{Args: &Get{}}
-		GetRequest(in, out)
-		return out.Args.(*Get).All[0].Dir
+		return out.All[0].Url, nil
 	}
+	return out.All[0].Dir, nil
 }
 
-func GetTrackersInfo(id int) []TrackersInfo {
+func GetTrackersInfo(id int) ([]TrackersInfo, error) {
 	type TorrentTrackers struct {
 		Trackers []TrackersInfo `json:"trackerStats"`
 	}
@@ -3040,212 +3778,191 @@ func GetTrackersInfo(id int) []TrackersInfo {
 	type TorrentsGetTrackersInfo struct {
 		Torrents []TorrentTrackers `json:"torrents"`
 	}
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"trackerStats"},
-			Ids:    []int{id},
-		},
-		Method: "torrent-get",
+	out := &TorrentsGetTrackersInfo{}
+	err := Client.TorrentGet([]int{id}, []string{"trackerStats"}, out)
+	if err != nil || len(out.Torrents) == 0 {
+		return nil, err
 	}
-	out := &Response{Args: &TorrentsGetTrackersInfo{}}
-	GetRequest(in, out)
-	return out.Args.(*TorrentsGetTrackersInfo).Torrents[0].Trackers
+	return out.Torrents[0].Trackers, nil
 }
 
-func GetContentInfo(id int) {
+// Return the files of the torrent and its download dir.
+func GetContentInfo(id int) ([]Content, string, error) {
 	type TorrentsGetContentInfo struct {
 		Torrents []TorrentContent `json:"torrents"`
 	}
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"downloadDir",
-				"fileStats", "files"},
-			Ids: []int{id},
-		},
-		Method: "torrent-get",
+	out := &TorrentsGetContentInfo{}
+	err := Client.TorrentGet([]int{id},
+		[]string{"downloadDir", "fileStats", "files"}, out)
+	if err != nil || len(out.Torrents) == 0 {
+		return nil, "", err
 	}
-	out := &Response{Args: &TorrentsGetContentInfo{}}
-	GetRequest(in, out)
-	files := out.Args.(*TorrentsGetContentInfo).Torrents[0].Files
-	fileStats := out.Args.(*TorrentsGetContentInfo).Torrents[0].FileStats
-	FilePath = out.Args.(*TorrentsGetContentInfo).Torrents[0].Path
+	files := out.Torrents[0].Files
+	fileStats := out.Torrents[0].FileStats
 
 	length := len(files)
-	Contents = make([]Content, length)
+	contents := make([]Content, length)
 	for i := 0; i < length; i++ {
-		Contents[i].Name = files[i].Name
-		Contents[i].Progress = files[i].Progress
-		Contents[i].Size = files[i].Size
-		Contents[i].Priority = fileStats[i].Priority
-		Contents[i].DlFlag = FormatWantedPre(fileStats[i].DlFlag)
-		Contents[i].Id = i
-	}
-	sort.Slice(Contents, func(i, j int) bool {
-		return Contents[i].Name < Contents[j].Name
+		contents[i].Name = files[i].Name
+		contents[i].Progress = files[i].Progress
+		contents[i].Size = files[i].Size
+		contents[i].Priority = fileStats[i].Priority
+		contents[i].DlFlag = FormatWantedPre(fileStats[i].DlFlag)
+		contents[i].Id = i
+	}
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].Name < contents[j].Name
 	})
+	return contents, out.Torrents[0].Path, nil
 }
 
-func GetPeersInfo(id int) []PeersInfo {
+func GetPeersInfo(id int) ([]PeersInfo, error) {
 	type TorrentPeers struct {
 		Peers []PeersInfo `json:"peers"`
 	}
 	type TorrentsGetPeersInfo struct {
 		Torrents []TorrentPeers `json:"torrents"`
 	}
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"peers"},
-			Ids:    []int{id},
-		},
-		Method: "torrent-get",
+	out := &TorrentsGetPeersInfo{}
+	err := Client.TorrentGet([]int{id}, []string{"peers"}, out)
+	if err != nil || len(out.Torrents) == 0 {
+		return nil, err
 	}
-	out := &Response{Args: &TorrentsGetPeersInfo{}}
-	GetRequest(in, out)
-	return out.Args.(*TorrentsGetPeersInfo).Torrents[0].Peers
+	return out.Torrents[0].Peers, nil
 }
 
-func GetGeneralInfo(id int) []*GeneralInfo {
+func GetGeneralInfo(id int) ([]*GeneralInfo, error) {
 	type TorrentsGetGeneralInfo struct {
 		All []*GeneralInfo `json:"torrents"`
 	}
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"name", "id", "uploadRatio",
-				"uploadedEver", "hashString",
-				"downloadDir", "comment", "creator",
-				"dateCreated", "addedDate", "totalSize",
-				"errorString", "labels"},
-			Ids: []int{id},
-		},
-		Method: "torrent-get",
-	}
-	out := &Response{Args: &TorrentsGetGeneralInfo{}}
-	GetRequest(in, out)
-	return out.Args.(*TorrentsGetGeneralInfo).All
-}
-
-func GetSessionStats() {
-	in := &Request{
-		Method: "session-stats",
-	}
-	out := &Response{Args: &SessionStats{}}
-	GetRequest(in, out)
-	Stats = out.Args.(*SessionStats)
-}
-
-func GetTorrents() {
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"id", "name", "labels", "addedDate"},
-		},
-		Method: "torrent-get",
-	}
-	out := &Response{Args: &TorrentsGet{}}
-	GetRequest(in, out)
-	Torrents = out.Args.(*TorrentsGet).All
-	sort.Slice(Torrents, func(i, j int) bool {
-		return strings.ToLower(Torrents[i].Name) < strings.ToLower(Torrents[j].Name)
-	})
-	GetTorrentsInfo()
+	out := &TorrentsGetGeneralInfo{}
+	err := Client.TorrentGet([]int{id}, []string{"name", "id",
+		"uploadRatio", "uploadedEver", "hashString", "downloadDir",
+		"comment", "creator", "dateCreated", "addedDate", "totalSize",
+		"errorString", "labels"}, out)
+	return out.All, err
 }
 
-func GetTorrentsInfo() {
-	in := &Request{
-		Args: Arg{
-			Fields: []string{"id", "sizeWhenDone", "error",
-				"percentDone", "status", "peersConnected",
-				"rateDownload", "rateUpload", "eta"},
-		},
-		Method: "torrent-get",
-	}
-	out := &Response{Args: &TorrentsGetInfo{}}
-	GetRequest(in, out)
-	TorrentsInfo := out.Args.(*TorrentsGetInfo).All
-	for _, t := range TorrentsInfo {
-		for _, s := range Torrents {
-			if s.Id == t.Id {
-				s.Desc = fmt.Sprintf(" %*s    %11s   %11s"+
-					"   %5s   %6s  %10s  %s %s",
-					StatFmt.Eta, FormatEta(t.Eta),
-					FormatSpeed(t.UplSpeed),
-					FormatSpeed(t.DlSpeed),
-					FormatPeers(t.Peers),
-					FormatProgress(t.Progress),
-					FormatSize(t.Size),
-					FormatStatus(t.Status, t.Error),
-					s.Name)
-				s.Status = t.Status
-				s.Progress = t.Progress
-				s.Size = t.Size
-				s.DlSpeed = t.DlSpeed
-				s.UplSpeed = t.UplSpeed
-				s.Error = t.Error
-			}
-		}
+func GetSessionStats() (*SessionStats, error) {
+	s := &SessionStats{}
+	if err := Client.SessionStats(s); err != nil {
+		return nil, err
 	}
+	return s, nil
 }
 
-func GetRequest(in *Request, out *Response, s ...[]byte) {
-	var pdata []byte
-	if len(s) == 0 {
-		var err error
-		pdata, err = json.Marshal(in)
-		if err != nil {
-			log.Fatal(err)
-		}
-	} else {
-		pdata = s[0]
-	}
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", URL, bytes.NewReader(pdata))
+// Return all torrents with their current stats.
+func GetTorrents() ([]*Torrent, error) {
+	out := &TorrentsGet{}
+	err := Client.TorrentGet(nil,
+		[]string{"id", "name", "labels", "addedDate"}, out)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	header := "X-Transmission-Session-Id"
-	req.Header.Set(header, HeaderId)
-	if AuthUsername != "" || AuthPasswd != "" {
-		req.SetBasicAuth(AuthUsername, AuthPasswd)
+	info, err := GetTorrentsInfo()
+	if err != nil {
+		return nil, err
 	}
-	resp, err := client.Do(req)
+	UpdateTorrentsInfo(out.All, info)
+	return out.All, nil
+}
+
+func GetTorrentsInfo() ([]*TorrentInfo, error) {
+	out := &TorrentsGetInfo{}
+	err := Client.TorrentGet(nil, InfoFields, out)
 	if err != nil {
-		if App != nil {
-			App.Stop()
-		}
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
-	defer func() {
-		resp.Body.Close()
-		if err != nil {
-			log.Fatal(err)
+	return out.All, nil
+}
+
+// Return the torrents changed recently with their stats and the ids of
+// the removed torrents.
+func GetRecentTorrents() ([]*Torrent, []*TorrentInfo, []int, error) {
+	type TorrentsGetRecent struct {
+		All     []json.RawMessage `json:"torrents"`
+		Removed []int             `json:"removed"`
+	}
+	out := &TorrentsGetRecent{}
+	fields := append([]string{"name", "labels", "addedDate"}, InfoFields...)
+	if err := Client.TorrentGetRecent(fields, out); err != nil {
+		return nil, nil, nil, err
+	}
+	torrents := make([]*Torrent, len(out.All))
+	info := make([]*TorrentInfo, len(out.All))
+	for i, raw := range out.All {
+		torrents[i], info[i] = &Torrent{}, &TorrentInfo{}
+		if err := json.Unmarshal(raw, torrents[i]); err != nil {
+			return nil, nil, nil, err
 		}
-	}()
-	if resp.StatusCode == 409 {
-		HeaderId = resp.Header.Get(header)
-		if len(s) == 0 {
-			GetRequest(in, out)
-		} else {
-			GetRequest(in, out, s[0])
+		if err := json.Unmarshal(raw, info[i]); err != nil {
+			return nil, nil, nil, err
 		}
-		return
-	} else if resp.StatusCode != 200 {
-		fmt.Fprintf(os.Stderr, "%d: %s\n", resp.StatusCode,
-			http.StatusText(resp.StatusCode))
 	}
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
+	return torrents, info, out.Removed, nil
+}
+
+func UpdateTorrentsInfo(torrents []*Torrent, TorrentsInfo []*TorrentInfo) {
+	index := make(map[int]*Torrent, len(torrents))
+	for _, s := range torrents {
+		index[s.Id] = s
 	}
-	err = json.Unmarshal(data, out)
-	if err != nil {
-		log.Fatal(err)
+	for _, t := range TorrentsInfo {
+		if s, ok := index[t.Id]; ok {
+			SetTorrentInfo(s, t)
+		}
+	}
+}
+
+// Set the stats of the torrent. Its description is formatted again when
+// it is shown.
+func SetTorrentInfo(s *Torrent, t *TorrentInfo) {
+	s.Desc = ""
+	s.Status = t.Status
+	s.Progress = t.Progress
+	s.Size = t.Size
+	s.DlSpeed = t.DlSpeed
+	s.UplSpeed = t.UplSpeed
+	s.Error = t.Error
+	s.Eta = t.Eta
+	s.Peers = t.Peers
+	s.Queue = t.Queue
+}
+
+func TorrentDesc(t *Torrent) string {
+	if t.Desc == "" {
+		t.Desc = fmt.Sprintf(" %*s    %11s   %11s"+
+			"   %5s   %6s  %10s  %s %*s     %s",
+			StatFmt.Eta, FormatEta(t.Eta),
+			FormatSpeed(t.UplSpeed),
+			FormatSpeed(t.DlSpeed),
+			FormatPeers(t.Peers),
+			FormatProgress(t.Progress),
+			FormatSize(t.Size),
+			FormatStatus(t.Status, t.Error),
+			StatFmt.Queue, FormatQueue(t), t.Name)
+	}
+	return t.Desc
+}
+
+// Show a failed RPC in the status bar until the next update.
+func ReportError(err error) {
+	Statusbar.SetText("[red:]" + tview.Escape(err.Error()) + "[-:]")
+}
+
+// Stop the interface and exit on an RPC error.
+func Fatal(err error) {
+	if App != nil {
+		App.Stop()
 	}
+	fmt.Fprintf(os.Stderr, "%v\n", err)
+	os.Exit(1)
 }
 
 var lng *message.Printer
 
-func P(text string) string {
-	return lng.Sprintf(text)
+func P(text string, a ...interface{}) string {
+	return lng.Sprintf(text, a...)
 }
 
 func SetLocales() {
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	BENCH_TORRENTS = 50000
+	BENCH_CHANGED  = 500 // Recently active torrents of an update.
+)
+
+// One refresh tick of a large daemon: merge the recently active torrents,
+// match the rows and format the visible ones.
+func BenchmarkRefresh(b *testing.B) {
+	lng = message.NewPrinter(language.English)
+	ALL = P("All")
+	StatSymb = &StatusSymbol{}
+	SelectedIds = make(map[int]int)
+	CurrentStatus = CurrStatus{ALL, STATUS_ALL}
+	CurrentCategory = ALL
+	torrents := make([]*Torrent, BENCH_TORRENTS)
+	for i := range torrents {
+		torrents[i] = &Torrent{Id: i + 1, Name: fmt.Sprintf("torrent-%d", i+1),
+			Status: STATUS_DOWNLOAD, Size: int64(i) << 20, Queue: i}
+	}
+	SetTorrents(torrents)
+	InitMainList()
+	MainList.SetRect(0, 0, 160, 40)
+	changed := make([]*Torrent, BENCH_CHANGED)
+	info := make([]*TorrentInfo, BENCH_CHANGED)
+	for i := range changed {
+		t := torrents[i*(BENCH_TORRENTS/BENCH_CHANGED)]
+		changed[i] = &Torrent{Id: t.Id, Name: t.Name}
+		info[i] = &TorrentInfo{Id: t.Id, Status: t.Status, Size: t.Size,
+			Queue: t.Queue, Progress: 0.5}
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, inf := range info {
+			inf.DlSpeed = n
+		}
+		MergeTorrents(changed, info, nil)
+		UpdateCurrentTorrents()
+		RenderRows()
+	}
+}